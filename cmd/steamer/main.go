@@ -0,0 +1,1048 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gellel/steamer"
+)
+
+const (
+	colorDebug   = "\033[0;36m%s\033[0m"
+	colorError   = "\033[1;31m%s\033[0m"
+	colorInfo    = "\033[1;34m%s\033[0m"
+	colorNotice  = "\033[1;36m%s\033[0m"
+	colorWarning = "\033[1;33m%s\033[0m"
+)
+
+var client = &http.Client{Timeout: time.Second * 10}
+
+var wg = &sync.WaitGroup{}
+
+var steamSearchQueryMap = &steamer.SteamSearchQueryMap{}
+
+var scanner = bufio.NewScanner(os.Stdin)
+
+var w = new(tabwriter.Writer).Init(os.Stdout, 0, 8, 0, '\t', 0)
+
+var pID = os.Getpid()
+
+type flagStrings []string
+
+func (flagStrings *flagStrings) String() string {
+	return strings.Join(*flagStrings, ",")
+}
+
+func (flagStrings *flagStrings) Set(value string) error {
+	*flagStrings = append(*flagStrings, value)
+	return nil
+}
+
+var flagFilters = &flagStrings{}
+
+func init() {
+	flag.Var(flagFilters, "filter", "-filter ACTION -filter SINGLE-PLAYER (repeatable)")
+}
+
+var (
+	flagAll               = flag.Bool("all", false, "-all (default false, discover -to from the first search page)")
+	flagCache             = flag.String("cache", "", "-cache path/to/cache (default '', revalidate revisited pages with ETag/Last-Modified)")
+	flagCategories        = flag.String("categories", "", "-categories 998,994 (default '')")
+	flagChartAfter        = flag.Duration("revisit-chart-after", 0, "-revisit-chart-after 24h (default 0, refetch charts visited longer ago)")
+	flagChartsConcurrency = flag.Int("charts-concurrency", 2, "-charts-concurrency 2")
+	flagChartsRPS         = flag.Float64("charts-rps", 1, "-charts-rps 1")
+	flagConfig            = flag.String("config", "", "-config path/to/profile.yaml (default '')")
+	flagController        = flag.String("controller", "", "-controller full|partial (default '')")
+	flagDepth             = flag.String("depth", "charts", "-depth search|store|charts")
+	flagExcludeTags       = flag.String("exclude-tags", "", "-exclude-tags 4085,12095 (default '')")
+	flagFarm              = flag.Int("farm", -1, "-farm 1")
+	flagGameAfter         = flag.Duration("revisit-game-after", 0, "-revisit-game-after 168h (default 0, refetch games visited longer ago)")
+	flagHideFreeToPlay    = flag.Bool("hide-free-to-play", false, "-hide-free-to-play (default false)")
+	flagJournal           = flag.String("journal", "", "-journal path/to/journal.jsonl (default '')")
+	flagLanguages         = flag.String("languages", "", "-languages english,french (default '')")
+	flagMaxPrice          = flag.String("max-price", "", "-max-price free|10 (default '')")
+	flagFormat            = flag.String("format", "table", "-format table|json")
+	flagOffline           = flag.Bool("offline", false, "-offline (default false)")
+	flagOS                = flag.String("os", "", "-os win,mac,linux (default '')")
+	flagOut               = flag.String("out", defaultOut(), "-out path/to/steambot")
+	flagPagesFrom         = flag.Int("from", -1, "-from 1")
+	flagPagesTo           = flag.Int("to", -1, "-to 2")
+	flagPageQuery         = flag.String("options", "", "-options 'tags=19' (default '')")
+	flagResume            = flag.String("resume", "", "-resume path/to/journal.jsonl (default '')")
+	flagRetry             = flag.Int("retry", 3, "-retry 3")
+	flagRetryBackoff      = flag.Duration("retry-backoff", time.Second, "-retry-backoff 1s")
+	flagRetryBackoffMax   = flag.Duration("retry-backoff-max", time.Second*30, "-retry-backoff-max 30s")
+	flagRetryJitter       = flag.Float64("retry-jitter", 0.5, "-retry-jitter 0.5")
+	flagRevisit           = flag.Int("revisit", -1, "-revisit (default -1, deprecated: use -revisit-*)")
+	flagRevisitChart      = flag.Bool("revisit-chart", false, "-revisit-chart (default false)")
+	flagRevisitGame       = flag.Bool("revisit-game", false, "-revisit-game (default false)")
+	flagRevisitSearch     = flag.Bool("revisit-search", false, "-revisit-search (default false)")
+	flagSearchAfter       = flag.Duration("revisit-search-after", 0, "-revisit-search-after 1h (default 0, refetch pages visited longer ago)")
+	flagSearchCount       = flag.Int("search-count", steamer.SteamSearchResultsCount, fmt.Sprintf("-search-count %d (rows per -search-mode infinite page)", steamer.SteamSearchResultsCount))
+	flagSearchMode        = flag.String("search-mode", "page", "-search-mode page|infinite")
+	flagSilent            = flag.Bool("silent", false, "-silent (default false)")
+	flagSortBy            = flag.String("sort-by", "", "-sort-by Released_DESC|Reviews_DESC|Price_ASC|Price_DESC|Name_ASC (default '')")
+	flagSpecials          = flag.Bool("specials", false, "-specials (default false)")
+	flagSQLite            = flag.String("sqlite", "", "-sqlite path/to/steamer.db (default '<out>/steamer.db')")
+	flagStore             = flag.String("store", "file", "-store file|sqlite")
+	flagStoreConcurrency  = flag.Int("store-concurrency", 4, "-store-concurrency 4")
+	flagStoreRPS          = flag.Float64("store-rps", 2, "-store-rps 2")
+	flagTags              = flag.String("tags", "", "-tags 19,492 (default '')")
+	flagTerm              = flag.String("term", "", "-term 'dark souls' (default '')")
+	flagTerminateZero     = flag.Bool("terminate-zero", false, "-terminate-zero (default false)")
+	flagVerbose           = flag.Bool("verbose", false, "-verbose (default false)")
+	flagWARC              = flag.String("warc", "", "-warc path/to/warc (default '', record fetched pages as .warc.gz)")
+	flagWARCReplay        = flag.String("warc-replay", "", "-warc-replay path/to/warc (default '', fetch pages from .warc.gz instead of the network)")
+	flagWARCSize          = flag.Int("warc-size", 1024, "-warc-size 1024 (MB per .warc.gz before rotating)")
+	flagWrite             = flag.Int("write", -1, "-write 0 (default -1, deprecated: use -write-*)")
+	flagWriteAbbreviation = flag.Bool("write-abbreviation", false, "-write-abbreviation (default false)")
+	flagWriteCSV          = flag.Bool("write-csv", true, "-write-csv (default true)")
+	flagWriteChart        = flag.Bool("write-chart", false, "-write-chart (default false)")
+	flagWriteGame         = flag.Bool("write-game", false, "-write-game (default false)")
+	flagWriteGameSummary  = flag.Bool("write-game-summary", false, "-write-game-summary (default false)")
+	flagWriteLog          = flag.Bool("write-log", true, "-write-log (default true)")
+	flagWriteSnapshot     = flag.Bool("write-snapshot", false, "-write-snapshot (default false)")
+	flagWriteSummary      = flag.Bool("write-summary", true, "-write-summary (default true)")
+)
+
+func defaultOut() string {
+	fullpath, err := steamer.DefaultFileStorePath()
+	if err != nil {
+		return "steambot"
+	}
+	return fullpath
+}
+
+func newSteamSearchQuery() (*steamer.SteamSearchQuery, error) {
+	categories, err := parseFlagInts("categories", *flagCategories)
+	if err != nil {
+		return nil, err
+	}
+	excludeTags, err := parseFlagInts("exclude-tags", *flagExcludeTags)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := parseFlagInts("tags", *flagTags)
+	if err != nil {
+		return nil, err
+	}
+	steamSearchQuery := &steamer.SteamSearchQuery{
+		Categories:         categories,
+		ControllerSupport:  *flagController,
+		ExcludeTags:        excludeTags,
+		HideFreeToPlay:     *flagHideFreeToPlay,
+		MaxPrice:           *flagMaxPrice,
+		OS:                 parseFlagList(*flagOS),
+		SortBy:             *flagSortBy,
+		Specials:           *flagSpecials,
+		SupportedLanguages: parseFlagList(*flagLanguages),
+		Tags:               tags,
+		Term:               *flagTerm}
+	return steamSearchQuery, steamSearchQuery.Validate()
+}
+
+func newSteamerConfig(filters []string, search *steamer.SteamSearchQuery, revisit *steamer.SteamerRevisit, write *steamer.SteamerWrite) *steamer.SteamerConfig {
+	return &steamer.SteamerConfig{
+		Cache:   *flagCache,
+		Depth:   *flagDepth,
+		Farm:    *flagFarm,
+		Filters: filters,
+		Journal: *flagJournal,
+		LimitCharts: steamer.SteamerHostLimit{
+			Concurrency:       *flagChartsConcurrency,
+			RequestsPerSecond: *flagChartsRPS},
+		LimitStore: steamer.SteamerHostLimit{
+			Concurrency:       *flagStoreConcurrency,
+			RequestsPerSecond: *flagStoreRPS},
+		Options:         *flagPageQuery,
+		Out:             *flagOut,
+		PagesAll:        *flagAll,
+		PagesFrom:       *flagPagesFrom,
+		PagesTo:         *flagPagesTo,
+		Retry:           *flagRetry,
+		RetryBackoff:    flagRetryBackoff.String(),
+		RetryBackoffMax: flagRetryBackoffMax.String(),
+		RetryJitter:     *flagRetryJitter,
+		Revisit:         revisit,
+		Search:          search,
+		SearchCount:     *flagSearchCount,
+		SearchMode:      *flagSearchMode,
+		SQLite:          *flagSQLite,
+		Store:           *flagStore,
+		TerminateZero:   *flagTerminateZero,
+		Verbose:         *flagVerbose,
+		WARC:            *flagWARC,
+		WARCReplay:      *flagWARCReplay,
+		WARCSize:        *flagWARCSize,
+		Write:           write}
+}
+
+func setSteamerConfigFlags(steamerConfig *steamer.SteamerConfig) error {
+	values := map[string][2]string{
+		"all":                {"pages_all", fmt.Sprintf("%t", steamerConfig.PagesAll)},
+		"cache":              {"cache", steamerConfig.Cache},
+		"charts-concurrency": {"limit_charts.concurrency", fmt.Sprintf("%d", steamerConfig.LimitCharts.Concurrency)},
+		"charts-rps":         {"limit_charts.requests_per_second", fmt.Sprintf("%v", steamerConfig.LimitCharts.RequestsPerSecond)},
+		"depth":              {"depth", steamerConfig.Depth},
+		"farm":               {"farm", fmt.Sprintf("%d", steamerConfig.Farm)},
+		"from":               {"pages_from", fmt.Sprintf("%d", steamerConfig.PagesFrom)},
+		"journal":            {"journal", steamerConfig.Journal},
+		"options":            {"options", steamerConfig.Options},
+		"out":                {"out", steamerConfig.Out},
+		"retry":              {"retry", fmt.Sprintf("%d", steamerConfig.Retry)},
+		"search-count":       {"search_count", fmt.Sprintf("%d", steamerConfig.SearchCount)},
+		"search-mode":        {"search_mode", steamerConfig.SearchMode},
+		"retry-backoff":      {"retry_backoff", steamerConfig.RetryBackoff},
+		"retry-backoff-max":  {"retry_backoff_max", steamerConfig.RetryBackoffMax},
+		"retry-jitter":       {"retry_jitter", fmt.Sprintf("%v", steamerConfig.RetryJitter)},
+		"sqlite":             {"sqlite", steamerConfig.SQLite},
+		"store":              {"store", steamerConfig.Store},
+		"store-concurrency":  {"limit_store.concurrency", fmt.Sprintf("%d", steamerConfig.LimitStore.Concurrency)},
+		"store-rps":          {"limit_store.requests_per_second", fmt.Sprintf("%v", steamerConfig.LimitStore.RequestsPerSecond)},
+		"terminate-zero":     {"terminate_zero", fmt.Sprintf("%t", steamerConfig.TerminateZero)},
+		"to":                 {"pages_to", fmt.Sprintf("%d", steamerConfig.PagesTo)},
+		"verbose":            {"verbose", fmt.Sprintf("%t", steamerConfig.Verbose)},
+		"warc":               {"warc", steamerConfig.WARC},
+		"warc-replay":        {"warc_replay", steamerConfig.WARCReplay},
+		"warc-size":          {"warc_size", fmt.Sprintf("%d", steamerConfig.WARCSize)}}
+	if revisit := steamerConfig.Revisit; revisit != nil {
+		values["revisit-chart"] = [2]string{"revisit.chart", fmt.Sprintf("%t", revisit.Chart)}
+		values["revisit-game"] = [2]string{"revisit.game", fmt.Sprintf("%t", revisit.Game)}
+		values["revisit-search"] = [2]string{"revisit.search", fmt.Sprintf("%t", revisit.Search)}
+		values["revisit-chart-after"] = [2]string{"revisit.chart_after", revisit.ChartAfter.String()}
+		values["revisit-game-after"] = [2]string{"revisit.game_after", revisit.GameAfter.String()}
+		values["revisit-search-after"] = [2]string{"revisit.search_after", revisit.SearchAfter.String()}
+	}
+	if write := steamerConfig.Write; write != nil {
+		values["write-abbreviation"] = [2]string{"write.abbreviation", fmt.Sprintf("%t", write.Abbreviation)}
+		values["write-csv"] = [2]string{"write.csv", fmt.Sprintf("%t", write.CSV)}
+		values["write-chart"] = [2]string{"write.chart", fmt.Sprintf("%t", write.Chart)}
+		values["write-game"] = [2]string{"write.game", fmt.Sprintf("%t", write.Game)}
+		values["write-game-summary"] = [2]string{"write.game_summary", fmt.Sprintf("%t", write.GameSummary)}
+		values["write-log"] = [2]string{"write.log", fmt.Sprintf("%t", write.Log)}
+		values["write-snapshot"] = [2]string{"write.snapshot", fmt.Sprintf("%t", write.Snapshot)}
+		values["write-summary"] = [2]string{"write.summary", fmt.Sprintf("%t", write.Summary)}
+	}
+	if search := steamerConfig.Search; search != nil {
+		values["categories"] = [2]string{"search.categories", joinFlagInts(search.Categories)}
+		values["controller"] = [2]string{"search.controller_support", search.ControllerSupport}
+		values["exclude-tags"] = [2]string{"search.exclude_tags", joinFlagInts(search.ExcludeTags)}
+		values["hide-free-to-play"] = [2]string{"search.hide_free_to_play", fmt.Sprintf("%t", search.HideFreeToPlay)}
+		values["languages"] = [2]string{"search.supported_languages", strings.Join(search.SupportedLanguages, ",")}
+		values["max-price"] = [2]string{"search.max_price", search.MaxPrice}
+		values["os"] = [2]string{"search.os", strings.Join(search.OS, ",")}
+		values["sort-by"] = [2]string{"search.sort_by", search.SortBy}
+		values["specials"] = [2]string{"search.specials", fmt.Sprintf("%t", search.Specials)}
+		values["tags"] = [2]string{"search.tags", joinFlagInts(search.Tags)}
+		values["term"] = [2]string{"search.term", search.Term}
+	}
+	visited := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		visited[f.Name] = true
+	})
+	for name, value := range values {
+		// command line flags win over the config file; keys the file leaves out stay unset so legacy levels still apply
+		if visited[name] || steamerConfig.Has(value[0]) != true {
+			continue
+		}
+		if err := flag.Set(name, value[1]); err != nil {
+			return fmt.Errorf("-config %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func joinFlagInts(n []int) string {
+	s := make([]string, len(n))
+	for i, x := range n {
+		s[i] = strconv.Itoa(x)
+	}
+	return strings.Join(s, ",")
+}
+
+func parseFlagInts(name string, value string) ([]int, error) {
+	n := []int{}
+	for _, x := range parseFlagList(value) {
+		ID, err := strconv.Atoi(x)
+		if err != nil {
+			return nil, fmt.Errorf("-%s %q not a number", name, x)
+		}
+		n = append(n, ID)
+	}
+	return n, nil
+}
+
+func parseFlagList(value string) []string {
+	s := []string{}
+	for _, x := range strings.Split(value, ",") {
+		if x = strings.TrimSpace(x); len(x) > 0 {
+			s = append(s, x)
+		}
+	}
+	return s
+}
+
+func visitedFlags(prefix string) bool {
+	var ok bool
+	flag.Visit(func(f *flag.Flag) {
+		if strings.HasPrefix(f.Name, prefix) {
+			ok = true
+		}
+	})
+	return ok
+}
+
+func requestInt() int {
+	if ok := scanner.Scan(); ok != true {
+		return 0
+	}
+	n, err := strconv.Atoi(scanner.Text())
+	if ok := err == nil; ok != true {
+		return 0
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+func requestFarmStrategy() int {
+	fmt.Println(fmt.Sprintf("[steam][%d]", pID), "farm strategy: (1/NIL)")
+	return requestInt()
+}
+
+func requestPagesFrom() int {
+	fmt.Println(fmt.Sprintf("[steam][%d]", pID), "search pages from", "\t", "->", "(MUST BE > 0)")
+	return requestInt()
+}
+
+func requestPagesTo() int {
+	fmt.Println(fmt.Sprintf("[steam][%d]", pID), "search pages to", "\t", "->", fmt.Sprintf("(MUST BE >= %d)", *flagPagesFrom))
+	return requestInt()
+}
+
+func requestRevisitStrategy() int {
+	fmt.Println(fmt.Sprintf("[steam][%d]", pID), "revisit page condition", "\t", "->", "(< 2 DONT REVISIT ALL)")
+	return requestInt()
+}
+
+func requestVerbosity() bool {
+	fmt.Println(fmt.Sprintf("[steam][%d]", pID), "be verbose", "\t", "->", "(YES/NO)")
+	if ok := scanner.Scan(); ok != true {
+		return false
+	}
+	t := strings.ToUpper(strings.TrimSpace(scanner.Text()))
+	return (t == "Y" || t == "YE" || t == "YES" || t == "1" || t == "OK")
+}
+
+func requestWriteStrategry() int {
+	fmt.Println(fmt.Sprintf("[steam][%d]", pID), "write document condition", "\t", "->", "(< 4 DONT WRITE ALL)")
+	return requestInt()
+}
+
+func newSnapshotRetry() *steamer.SnapshotRetry {
+	return steamer.NewSnapshotRetry(*flagRetry, *flagRetryBackoff, *flagRetryBackoffMax, *flagRetryJitter)
+}
+
+func newSteamerLimits() map[string]steamer.SteamerHostLimit {
+	// replayed pages come from disk, so the per-host limits would only slow them down
+	if len(*flagWARCReplay) > 0 {
+		return nil
+	}
+	return map[string]steamer.SteamerHostLimit{
+		steamer.SteamChartsHost: {
+			Concurrency:       *flagChartsConcurrency,
+			RequestsPerSecond: *flagChartsRPS},
+		steamer.SteamStoreHost: {
+			Concurrency:       *flagStoreConcurrency,
+			RequestsPerSecond: *flagStoreRPS}}
+}
+
+func newCacheTransport(transport http.RoundTripper) http.RoundTripper {
+	if len(*flagCache) == 0 {
+		return transport
+	}
+	steamerCache, err := steamer.NewSteamerCache(*flagCache, transport)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("[steam][%d]", pID), "cache", "\t", "->", err)
+		os.Exit(1)
+	}
+	return steamerCache
+}
+
+func requestPageQuery(ctx context.Context, c *http.Client, r *steamer.SnapshotRetry) string {
+	var queryString string
+	steamSearchFilterCatalog, err := steamer.LoadSteamSearchFilterCatalog(ctx, c, r, steamer.NewFileStore(*flagOut), *flagOffline)
+	if err != nil {
+		return queryString
+	}
+	fmt.Println(fmt.Sprintf("[steam][%d]", pID), "use filters", "\t", "->", "(YES/NO)")
+	if ok := scanner.Scan(); ok != true {
+		return queryString
+	}
+	t := strings.ToUpper(scanner.Text())
+	ok := (t == "Y" || t == "YE" || t == "YES" || t == "1" || t == "OK")
+	if ok != true {
+		return queryString
+	}
+	steamSearchQueryMap = steamSearchFilterCatalog.QueryMap()
+	fmt.Println(fmt.Sprintf("[steam][%d]", pID), "show filters", "\t", "->", "(YES/NO)")
+	if ok := scanner.Scan(); ok != true {
+		return queryString
+	}
+	t = strings.ToUpper(scanner.Text())
+	ok = (t == "Y" || t == "YE" || t == "YES" || t == "1" || t == "OK")
+	if ok {
+		mapLen := len(*steamSearchQueryMap)
+		queryMapKeys := make([]string, mapLen)
+		i := 0
+		for key := range *steamSearchQueryMap {
+			queryMapKeys[i] = key
+			i = i + 1
+		}
+		sort.Strings(queryMapKeys)
+		for _, key := range queryMapKeys {
+			fmt.Println(key)
+		}
+	}
+	fmt.Println(fmt.Sprintf("[steam][%d]", pID), "search filters", "\t", "->", "(SEPARATE FILTER USING SPACES)")
+	if ok := scanner.Scan(); ok != true {
+		return queryString
+	}
+	steamSearchQuery := &steamer.SteamSearchQuery{
+		Params: map[string][]string{}}
+	terms := []string{}
+	for _, dataLoc := range strings.Split(strings.ToUpper(scanner.Text()), " ") {
+		if len(dataLoc) == 0 {
+			continue
+		}
+		steamSearchKeyValue, ok := steamSearchQueryMap.Get(dataLoc)
+		if ok != true {
+			terms = append(terms, dataLoc)
+			continue
+		}
+		key := steamSearchKeyValue.Key
+		steamSearchQuery.Params[key] = append(steamSearchQuery.Params[key], steamSearchKeyValue.Value)
+	}
+	steamSearchQuery.Term = strings.Join(terms, " ")
+	queryString = steamSearchQuery.Encode()
+	return queryString
+}
+
+func commandFilters(ctx context.Context) error {
+	c := &http.Client{
+		Timeout:   client.Timeout,
+		Transport: newCacheTransport(steamer.NewSteamerLimiter(client.Transport, newSteamerLimits()))}
+	steamSearchFilterCatalogUpdate, err := steamer.UpdateSteamSearchFilterCatalog(ctx, c, newSnapshotRetry(), steamer.NewFileStore(*flagOut), *flagOffline)
+	if err != nil {
+		return err
+	}
+	steamSearchFilterCatalog := steamSearchFilterCatalogUpdate.SteamSearchFilterCatalog
+	steamSearchFilterCatalogDiff := steamSearchFilterCatalogUpdate.Diff
+	previous := steamSearchFilterCatalogUpdate.Previous
+	switch *flagFormat {
+	case "json":
+		b, err := json.MarshalIndent(steamSearchFilterCatalogUpdate, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	case "table":
+		fmt.Fprintln(w, "KEY", "\t", "PARAM", "\t", "VALUE", "\t", "NAME", "\t", "GROUP")
+		for _, x := range steamSearchFilterCatalog.Filters {
+			fmt.Fprintln(w, x.Key, "\t", x.Param, "\t", x.Value, "\t", x.Name, "\t", x.Group)
+		}
+		w.Flush()
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "version", "\t", "->", steamSearchFilterCatalog.Version)
+		if previous != nil && previous.Version != steamSearchFilterCatalog.Version {
+			fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "previous", "\t", "->", previous.Version)
+		}
+		for _, x := range steamSearchFilterCatalogDiff.Added {
+			fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "added", "\t", "->", x.Key, fmt.Sprintf("(%s=%s)", x.Param, x.Value))
+		}
+		for _, x := range steamSearchFilterCatalogDiff.Changed {
+			fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "changed", "\t", "->", x.Key, fmt.Sprintf("(%s=%s)", x.Param, x.Value))
+		}
+		for _, x := range steamSearchFilterCatalogDiff.Removed {
+			fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "removed", "\t", "->", x.Key, fmt.Sprintf("(%s=%s)", x.Param, x.Value))
+		}
+		w.Flush()
+	default:
+		return fmt.Errorf("-format %q unknown", *flagFormat)
+	}
+	return nil
+}
+
+func commandReparse() error {
+	var (
+		replayStore steamer.ReplayStore
+		store       steamer.Store
+	)
+	switch *flagStore {
+	case "file":
+		fileStore := steamer.NewFileStore(*flagOut)
+		replayStore, store = fileStore, fileStore
+	case "sqlite":
+		if len(*flagSQLite) == 0 {
+			*flagSQLite = filepath.Join(*flagOut, "steamer.db")
+		}
+		sqliteStore, err := steamer.NewSQLiteStore(*flagSQLite, steamer.NewSteamerRunID())
+		if err != nil {
+			return err
+		}
+		defer sqliteStore.Close()
+		replayStore, store = sqliteStore, sqliteStore
+	default:
+		return fmt.Errorf("-store %q unknown", *flagStore)
+	}
+	steamerReparse, err := steamer.Reparse(replayStore, store)
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "out", "\t", "->", *flagOut)
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "abbreviations", "\t", "->", steamerReparse.Abbreviations)
+	if steamerReparse.AbbreviationsSkipped > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "abbreviationsSkipped", "\t", "->", steamerReparse.AbbreviationsSkipped)
+	}
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "games", "\t", "->", steamerReparse.Games)
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "charts", "\t", "->", steamerReparse.Charts)
+	for _, skipped := range steamerReparse.Skipped {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "skipped", "\t", "->", skipped)
+	}
+	for _, failed := range steamerReparse.Failed {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "failed", "\t", "->", failed)
+	}
+	w.Flush()
+	return err
+}
+
+func main() {
+
+	flag.Parse()
+
+	if ok := flag.Parsed(); ok != true {
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	command := flag.Arg(0)
+
+	switch command {
+	case "":
+	case "migrate":
+		fileStoreMigration, err := steamer.NewFileStore(*flagOut).Migrate()
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "out", "\t", "->", *flagOut)
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "moved", "\t", "->", fileStoreMigration.Moved)
+		for _, skipped := range fileStoreMigration.Skipped {
+			fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "skipped", "\t", "->", skipped)
+		}
+		w.Flush()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	case "reparse":
+		if err := commandReparse(); err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "reparse", "\t", "->", err)
+			os.Exit(1)
+		}
+		return
+	case "filters":
+		if err := commandFilters(ctx); err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "filters", "\t", "->", err)
+			os.Exit(1)
+		}
+		return
+	case "retry-failed":
+		if flag.NArg() < 2 {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "retry-failed", "\t", "->", "(MISSING DEADLETTER FILE)")
+			os.Exit(2)
+		}
+	default:
+		fmt.Println(fmt.Sprintf("[steam][%d]", pID), "command", "\t", "->", fmt.Sprintf("(UNKNOWN %q)", command))
+		os.Exit(2)
+	}
+
+	var filters []string
+	var searchParams map[string][]string
+	if len(*flagConfig) > 0 {
+		steamerConfig := newSteamerConfig(nil, nil, &steamer.SteamerRevisit{
+			Chart:       *flagRevisitChart,
+			ChartAfter:  steamer.SteamerDuration(*flagChartAfter),
+			Game:        *flagRevisitGame,
+			GameAfter:   steamer.SteamerDuration(*flagGameAfter),
+			Search:      *flagRevisitSearch,
+			SearchAfter: steamer.SteamerDuration(*flagSearchAfter)}, &steamer.SteamerWrite{
+			Abbreviation: *flagWriteAbbreviation,
+			CSV:          *flagWriteCSV,
+			Chart:        *flagWriteChart,
+			Game:         *flagWriteGame,
+			GameSummary:  *flagWriteGameSummary,
+			Log:          *flagWriteLog,
+			Snapshot:     *flagWriteSnapshot,
+			Summary:      *flagWriteSummary})
+		err := steamerConfig.Read(*flagConfig)
+		if err == nil {
+			err = setSteamerConfigFlags(steamerConfig)
+		}
+		if err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "config", "\t", "->", err)
+			os.Exit(2)
+		}
+		filters = steamerConfig.Filters
+		if steamerConfig.Search != nil {
+			searchParams = steamerConfig.Search.Params
+		}
+	}
+
+	if len(*flagWARCReplay) > 0 {
+		steamerWARCTransport, err := steamer.NewSteamerWARCTransport(*flagWARCReplay)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "warc-replay", "\t", "->", err)
+			os.Exit(1)
+		}
+		client.Transport = steamerWARCTransport
+	}
+
+	// the crawl and the requests made before it go through one limiter; the cache sits outside it, so hits are served without waiting for a slot
+	steamerLimiter := steamer.NewSteamerLimiter(client.Transport, newSteamerLimits())
+	crawlerClient := &http.Client{
+		Timeout:   client.Timeout,
+		Transport: newCacheTransport(steamerLimiter)}
+
+	if len(*flagFilters) > 0 {
+		filters = *flagFilters
+	}
+
+	if len(filters) > 0 {
+		steamSearchFilterCatalog, err := steamer.LoadSteamSearchFilterCatalog(ctx, crawlerClient, newSnapshotRetry(), steamer.NewFileStore(*flagOut), *flagOffline)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "filters", "\t", "->", err)
+			os.Exit(1)
+		}
+		queryString, err := steamSearchFilterCatalog.QueryMap().Query(filters)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "filters", "\t", "->", err)
+			os.Exit(2)
+		}
+		if len(*flagPageQuery) > 0 {
+			queryString = fmt.Sprintf("%s&%s", *flagPageQuery, queryString)
+		}
+		*flagPageQuery = queryString
+	}
+
+	steamSearchQuery, err := newSteamSearchQuery()
+	if err == nil {
+		steamSearchQuery.Params = searchParams
+		err = steamSearchQuery.Merge(*flagPageQuery)
+	}
+	if err != nil {
+		fmt.Println(fmt.Sprintf("[steam][%d]", pID), "search", "\t", "->", err)
+		os.Exit(2)
+	}
+	*flagPageQuery = steamSearchQuery.Encode()
+
+	depths := map[string]string{
+		"charts": steamer.SteamerStageChart,
+		"search": steamer.SteamerStageSearch,
+		"store":  steamer.SteamerStageGame}
+	if _, ok := depths[*flagDepth]; ok != true {
+		fmt.Println(fmt.Sprintf("[steam][%d]", pID), "depth", "\t", "->", fmt.Sprintf("(UNKNOWN %q)", *flagDepth))
+		os.Exit(2)
+	}
+
+	switch *flagStore {
+	case "file", "sqlite":
+	default:
+		fmt.Println(fmt.Sprintf("[steam][%d]", pID), "store", "\t", "->", fmt.Sprintf("(UNKNOWN %q)", *flagStore))
+		os.Exit(2)
+	}
+
+	if len(*flagSQLite) == 0 {
+		*flagSQLite = filepath.Join(*flagOut, "steamer.db")
+	}
+
+	if len(*flagResume) > 0 || command == "retry-failed" {
+		*flagFarm = 0
+		*flagSilent = true
+	}
+
+	if *flagPagesFrom == -1 && *flagSilent != true {
+		*flagPagesFrom = requestPagesFrom()
+	}
+
+	// -search-mode infinite knows its total count, so an unset -to means every result
+	if *flagPagesTo == -1 && *flagSearchMode == steamer.SteamerSearchInfinite {
+		*flagAll = true
+	}
+
+	if *flagPagesTo == -1 && *flagSilent != true && *flagAll != true {
+		*flagPagesTo = requestPagesTo()
+	}
+
+	if *flagPageQuery == "" && *flagSilent != true {
+		*flagPageQuery = requestPageQuery(ctx, crawlerClient, newSnapshotRetry())
+	}
+
+	if *flagPagesFrom <= 0 {
+		*flagPagesFrom = 1
+	}
+
+	if *flagPagesTo <= 0 {
+		*flagPagesTo = 1
+	}
+
+	if ok := *flagPagesFrom > *flagPagesTo; ok {
+		*flagPagesTo, *flagPagesFrom = *flagPagesFrom, *flagPagesTo
+	}
+
+	if *flagRevisit == -1 && *flagSilent != true && visitedFlags("revisit-") != true {
+		*flagRevisit = requestRevisitStrategy()
+	}
+
+	if *flagWrite == -1 && *flagSilent != true && visitedFlags("write-") != true {
+		*flagWrite = requestWriteStrategry()
+	}
+
+	revisit := steamer.NewSteamerRevisit(int(math.Abs(float64(*flagRevisit))))
+	write := steamer.NewSteamerWrite(*flagWrite)
+	// the legacy levels never reach past -depth; only the named flags can conflict with it
+	if depths[*flagDepth] != steamer.SteamerStageChart {
+		revisit.Chart, write.Chart = false, false
+	}
+	if depths[*flagDepth] == steamer.SteamerStageSearch {
+		revisit.Game, write.Game = false, false
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "revisit-chart":
+			revisit.Chart = *flagRevisitChart
+		case "revisit-chart-after":
+			revisit.ChartAfter = steamer.SteamerDuration(*flagChartAfter)
+		case "revisit-game":
+			revisit.Game = *flagRevisitGame
+		case "revisit-game-after":
+			revisit.GameAfter = steamer.SteamerDuration(*flagGameAfter)
+		case "revisit-search":
+			revisit.Search = *flagRevisitSearch
+		case "revisit-search-after":
+			revisit.SearchAfter = steamer.SteamerDuration(*flagSearchAfter)
+		case "write-abbreviation":
+			write.Abbreviation = *flagWriteAbbreviation
+		case "write-csv":
+			write.CSV = *flagWriteCSV
+		case "write-chart":
+			write.Chart = *flagWriteChart
+		case "write-game":
+			write.Game = *flagWriteGame
+		case "write-game-summary":
+			write.GameSummary = *flagWriteGameSummary
+		case "write-log":
+			write.Log = *flagWriteLog
+		case "write-snapshot":
+			write.Snapshot = *flagWriteSnapshot
+		case "write-summary":
+			write.Summary = *flagWriteSummary
+		}
+	})
+
+	if err := (&steamer.CrawlerOptions{Depth: depths[*flagDepth], Revisit: revisit, SearchCount: *flagSearchCount, SearchMode: *flagSearchMode, Write: write}).Validate(); err != nil {
+		fmt.Println(fmt.Sprintf("[steam][%d]", pID), "options", "\t", "->", err)
+		os.Exit(2)
+	}
+
+	if *flagFarm == -1 && *flagSilent != true {
+		*flagFarm = requestFarmStrategy()
+	}
+
+	if *flagSilent != true && *flagVerbose == false {
+		*flagVerbose = requestVerbosity()
+	}
+
+	// the farm splits the discovered range, so both halves receive an explicit -from/-to
+	if *flagFarm == 1 && *flagAll {
+		steamSearchPagination, err := steamer.GetSteamSearchPagination(ctx, crawlerClient, newSnapshotRetry(), *flagSearchMode, *flagPageQuery, *flagPagesFrom, *flagSearchCount)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "all", "\t", "->", err)
+			os.Exit(1)
+		}
+		*flagAll = false
+		*flagPagesTo = steamSearchPagination.LastPage
+		if *flagPagesTo < *flagPagesFrom {
+			*flagPagesTo = *flagPagesFrom
+		}
+	}
+
+	switch *flagFarm {
+	case 1:
+		*flagChartsRPS = (*flagChartsRPS / 2)
+		*flagStoreRPS = (*flagStoreRPS / 2)
+		args := []string{
+			"-silent",
+			"-depth",
+			*flagDepth,
+			"-from",
+			fmt.Sprintf("%d", (*flagPagesTo/2)+1),
+			"-to",
+			fmt.Sprintf("%d", *flagPagesTo),
+			"-options",
+			fmt.Sprintf("%s", *flagPageQuery),
+			"-search-mode",
+			*flagSearchMode,
+			"-cache",
+			*flagCache,
+			"-warc",
+			*flagWARC,
+			"-warc-replay",
+			*flagWARCReplay,
+			"-warc-size",
+			fmt.Sprintf("%d", *flagWARCSize),
+			"-search-count",
+			fmt.Sprintf("%d", *flagSearchCount),
+			fmt.Sprintf("-revisit-chart=%t", revisit.Chart),
+			fmt.Sprintf("-revisit-game=%t", revisit.Game),
+			fmt.Sprintf("-revisit-search=%t", revisit.Search),
+			fmt.Sprintf("-revisit-chart-after=%s", revisit.ChartAfter),
+			fmt.Sprintf("-revisit-game-after=%s", revisit.GameAfter),
+			fmt.Sprintf("-revisit-search-after=%s", revisit.SearchAfter),
+			fmt.Sprintf("-write-abbreviation=%t", write.Abbreviation),
+			fmt.Sprintf("-write-csv=%t", write.CSV),
+			fmt.Sprintf("-write-chart=%t", write.Chart),
+			fmt.Sprintf("-write-game=%t", write.Game),
+			fmt.Sprintf("-write-game-summary=%t", write.GameSummary),
+			fmt.Sprintf("-write-log=%t", write.Log),
+			fmt.Sprintf("-write-snapshot=%t", write.Snapshot),
+			fmt.Sprintf("-write-summary=%t", write.Summary),
+			"-out",
+			*flagOut,
+			"-store",
+			*flagStore,
+			"-sqlite",
+			*flagSQLite,
+			"-charts-concurrency",
+			fmt.Sprintf("%d", *flagChartsConcurrency),
+			"-charts-rps",
+			fmt.Sprintf("%g", *flagChartsRPS),
+			"-store-concurrency",
+			fmt.Sprintf("%d", *flagStoreConcurrency),
+			"-store-rps",
+			fmt.Sprintf("%g", *flagStoreRPS),
+			"-retry",
+			fmt.Sprintf("%d", *flagRetry),
+			"-retry-backoff",
+			flagRetryBackoff.String(),
+			"-retry-backoff-max",
+			flagRetryBackoffMax.String(),
+			"-retry-jitter",
+			fmt.Sprintf("%g", *flagRetryJitter),
+			fmt.Sprintf("-terminate-zero=%t", *flagTerminateZero),
+			fmt.Sprintf("-verbose=%t", *flagVerbose)}
+		// both halves append whole lines to a shared -journal, so one -resume picks up either half
+		if len(*flagJournal) > 0 {
+			args = append(args, "-journal", *flagJournal)
+		}
+		cmd := exec.CommandContext(ctx, os.Args[0], args...)
+		cmd.Cancel = func() error {
+			return cmd.Process.Signal(syscall.SIGTERM)
+		}
+		cmd.Stdout = os.Stdout
+		cmd.Stdin = os.Stdin
+		cmd.Stderr = os.Stderr
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd.Run()
+		}()
+		*flagPagesTo = (*flagPagesTo / 2)
+		steamerLimiter.SetLimits(newSteamerLimits())
+	}
+
+	runID := steamer.NewSteamerRunID()
+
+	crawlerOptions := &steamer.CrawlerOptions{
+		Depth:         depths[*flagDepth],
+		Limiter:       steamerLimiter,
+		Limits:        newSteamerLimits(),
+		PageQuery:     *flagPageQuery,
+		PagesAll:      *flagAll,
+		PagesFrom:     *flagPagesFrom,
+		PagesTo:       *flagPagesTo,
+		Retry:         newSnapshotRetry(),
+		Revisit:       revisit,
+		RunID:         runID,
+		SearchCount:   *flagSearchCount,
+		SearchMode:    *flagSearchMode,
+		TerminateZero: *flagTerminateZero,
+		Verbose:       *flagVerbose,
+		Write:         write}
+
+	var store steamer.Store
+	switch *flagStore {
+	case "sqlite":
+		sqliteStore, err := steamer.NewSQLiteStore(*flagSQLite, runID)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "sqlite", "\t", "->", err)
+			os.Exit(1)
+		}
+		defer sqliteStore.Close()
+		store = sqliteStore
+	default:
+		fileStore := steamer.NewFileStore(*flagOut)
+		defer fileStore.Close()
+		store = fileStore
+	}
+
+	crawler := steamer.NewCrawler(crawlerClient, store, crawlerOptions)
+	crawler.Log.Config = newSteamerConfig(filters, steamSearchQuery, revisit, write)
+
+	if len(*flagWARC) > 0 {
+		steamerWARC, err := steamer.NewSteamerWARC(*flagWARC, runID, int64(*flagWARCSize)<<20)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "warc", "\t", "->", err)
+			os.Exit(1)
+		}
+		defer steamerWARC.Close()
+		crawler.WARC = steamerWARC
+	}
+
+	journalName := *flagJournal
+	if len(*flagResume) > 0 {
+		steamerJournalEntries, err := steamer.ReadSteamerJournal(*flagResume)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "resume", "\t", "->", err)
+			os.Exit(1)
+		}
+		crawler.Resume(steamerJournalEntries)
+		journalName = *flagResume
+	}
+	if command == "retry-failed" {
+		steamerDeadLetter, err := steamer.ReadSteamerDeadLetter(flag.Arg(1))
+		if err != nil {
+			fmt.Println(fmt.Sprintf("[steam][%d]", pID), "retry-failed", "\t", "->", err)
+			os.Exit(1)
+		}
+		crawler.RetryFailed(steamerDeadLetter)
+	}
+	if len(journalName) == 0 {
+		journalName = filepath.Join(*flagOut, fmt.Sprintf("%d-%d-%d-journal.jsonl", time.Now().UnixNano(), crawler.Options.PagesFrom, crawler.Options.PagesTo))
+	}
+	crawler.Journal, err = steamer.NewSteamerJournal(journalName)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("[steam][%d]", pID), "journal", "\t", "->", err)
+		os.Exit(1)
+	}
+	defer crawler.Journal.Close()
+
+	var farmStrategy string
+	switch *flagFarm {
+	case 1:
+		farmStrategy = "EVEN"
+	default:
+		farmStrategy = "NONE"
+	}
+
+	if len(*flagConfig) > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "config", "\t", "->", *flagConfig)
+	}
+
+	if len(filters) > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "filters", "\t", "->", strings.Join(filters, " "), fmt.Sprintf("(%s)", *flagPageQuery))
+	}
+
+	if len(*flagPageQuery) > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "search", "\t", "->", crawler.Options.PageQuery)
+	}
+
+	if crawler.Options.SearchMode == steamer.SteamerSearchInfinite {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "search mode", "\t", "->", strings.ToUpper(crawler.Options.SearchMode), fmt.Sprintf("(%d PER PAGE)", crawler.Options.SearchCount))
+	}
+
+	pages := fmt.Sprintf("%d..%d", crawler.Options.PagesFrom, crawler.Options.PagesTo)
+	if crawler.Options.PagesAll {
+		pages = fmt.Sprintf("%d..ALL", crawler.Options.PagesFrom)
+	}
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "pages", "\t", "->", pages)
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "farm", "\t", "->", farmStrategy)
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "depth", "\t", "->", strings.ToUpper(*flagDepth))
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "revisit", "\t", "->", crawler.Options.Revisit)
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "write", "\t", "->", crawler.Options.Write)
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "limit", "\t", "->", steamer.SteamStoreHost, fmt.Sprintf("%d @ %g/s", *flagStoreConcurrency, *flagStoreRPS))
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "limit", "\t", "->", steamer.SteamChartsHost, fmt.Sprintf("%d @ %g/s", *flagChartsConcurrency, *flagChartsRPS))
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "retry", "\t", "->", fmt.Sprintf("%d x %s..%s", *flagRetry, *flagRetryBackoff, *flagRetryBackoffMax))
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "out", "\t", "->", *flagOut)
+
+	if *flagStore == "sqlite" {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "sqlite", "\t", "->", *flagSQLite)
+	}
+
+	if len(*flagCache) > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "cache", "\t", "->", *flagCache)
+	}
+
+	if len(*flagWARC) > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "warc", "\t", "->", *flagWARC, fmt.Sprintf("%dMB", *flagWARCSize))
+	}
+
+	if len(*flagWARCReplay) > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "warc-replay", "\t", "->", *flagWARCReplay)
+	}
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "runID", "\t", "->", crawler.Log.RunID)
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "journal", "\t", "->", crawler.Journal.Name)
+
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "timeStart", "\t", "->", crawler.Log.TimeStart)
+
+	w.Flush()
+
+	go func() {
+		<-ctx.Done()
+		stop()
+		fmt.Println(fmt.Sprintf("[steam][%d]", pID), "shutdown", "\t", "->", "DRAINING (SIGNAL AGAIN TO EXIT)")
+	}()
+
+	if err := crawler.Run(ctx); err != nil {
+		fmt.Println(err)
+	}
+	wg.Wait()
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "timeEnd", "\t", "->", crawler.Log.TimeEnd)
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "timeDuration", "\t", "->", crawler.Log.TimeDuration)
+	fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "failures", "\t", "->", crawler.Log.Failures)
+	if crawler.Log.Stored > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "stored", "\t", "->", crawler.Log.Stored)
+	}
+	if crawler.Log.WARCFailures > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "warcFailures", "\t", "->", crawler.Log.WARCFailures)
+	}
+	if crawler.Log.TerminateZero {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "terminateZero", "\t", "->", "STOPPED ON EMPTY PAGE")
+	}
+	if crawler.Log.Incomplete {
+		fmt.Fprintln(w, fmt.Sprintf("[steam][%d]", pID), "incomplete", "\t", "->", "INTERRUPTED")
+	}
+	w.Flush()
+	time.Sleep(time.Second)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/gellel/steamer"
+)
+
+func TestSetSteamerConfigFlags(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "profile.yaml")
+	profile := "pages_from: 2\npages_to: 9\nsearch:\n  term: half\nwrite:\n  csv: false\n"
+	if err := ioutil.WriteFile(name, []byte(profile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	steamerConfig := &steamer.SteamerConfig{}
+	if err := steamerConfig.Read(name); err != nil {
+		t.Fatal(err)
+	}
+	// -to is on the command line, so it wins over pages_to
+	if err := flag.Set("to", "5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := setSteamerConfigFlags(steamerConfig); err != nil {
+		t.Fatalf("setSteamerConfigFlags: %v", err)
+	}
+	if *flagPagesFrom != 2 {
+		t.Errorf("-from = %d, want 2 from the config", *flagPagesFrom)
+	}
+	if *flagPagesTo != 5 {
+		t.Errorf("-to = %d, want 5 from the command line", *flagPagesTo)
+	}
+	if *flagTerm != "half" {
+		t.Errorf("-term = %q, want %q from the config", *flagTerm, "half")
+	}
+	// a config key equal to the zero value still replaces a true flag default
+	if *flagWriteCSV != false {
+		t.Error("-write-csv = true, want false from the config")
+	}
+	// keys the config leaves out keep the flag default
+	if *flagDepth != "charts" || *flagWriteLog != true {
+		t.Errorf("-depth, -write-log = %q, %t, want the defaults", *flagDepth, *flagWriteLog)
+	}
+}
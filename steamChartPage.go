@@ -1,14 +1,13 @@
-package main
+package steamer
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"net/url"
 	"os"
-	"os/user"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -19,6 +18,7 @@ import (
 )
 
 type SteamChartPage struct {
+	AppID            int                    `json:"app_ID"`
 	Delta            time.Time              `json:"delta"`
 	Growth           []SteamChartGameGrowth `json:"growth"`
 	Name             string                 `json:"name"`
@@ -26,6 +26,7 @@ type SteamChartPage struct {
 	PlayerPeakAll    int                    `json:"player_peek_all"`
 	PlayerPeakDelta  int                    `json:"player_peek_delta"`
 	Timestamp        time.Time              `json:"timestamp"`
+	Title            string                 `json:"title"`
 	URL              string                 `json:"URL"`
 }
 
@@ -36,18 +37,17 @@ func NewSteamChartPage(s *goquery.Selection) *SteamChartPage {
 		Name:             scrapeSteamChartGameName(s),
 		PlayerPeakAll:    scrapeSteamChartGamePlayerPeakAll(s),
 		PlayerPeak24Hour: scrapeSteamChartGamePlayerPeak24Hour(s),
-		PlayerPeakDelta:  scrapeSteamChartGamePlayerPeakDelta(s)}
+		PlayerPeakDelta:  scrapeSteamChartGamePlayerPeakDelta(s),
+		Timestamp:        time.Now(),
+		Title:            scrapeSteamChartGameTitle(s)}
 }
 
-func onGetSteamChartPage(c *http.Client, URL string, revisit bool, snap func(s *Snapshot), success func(s *SteamChartPage), err func(e error)) {
-	if revisit == false {
-		if u, err := url.Parse(URL); err == nil {
-			if ok, _ := hasVisitedURLDefault(u); ok {
-				return
-			}
-		}
+func (crawler *Crawler) onGetSteamChartPage(ctx context.Context, URL string, revisit bool, snap func(s *Snapshot), success func(s *SteamChartPage), err func(e error)) {
+	snapshot := crawler.snapshot(ctx, URL, revisit, nil)
+	if ok := (ctx.Err() != nil && snapshot.StatusCode == 0); ok {
+		err(ctx.Err())
+		return
 	}
-	snapshot := NewSnapshot(c, http.MethodGet, URL, nil)
 	snap(snapshot)
 	if ok := (snapshot.StatusCode == http.StatusOK); ok != true {
 		err(errors.New(snapshot.Status))
@@ -57,19 +57,28 @@ func onGetSteamChartPage(c *http.Client, URL string, revisit bool, snap func(s *
 		err(snapshot.ErrDoc)
 		return
 	}
+	steamChartPage, e := newSteamChartPageFromDocument(URL, snapshot.document)
+	if e != nil {
+		err(e)
+		return
+	}
+	success(steamChartPage)
+}
+
+func newSteamChartPageFromDocument(URL string, doc *goquery.Document) (*SteamChartPage, error) {
 	CSSSelector := "html"
-	goQuerySelection := snapshot.document.Find(CSSSelector)
+	goQuerySelection := doc.Find(CSSSelector)
 	goQuerySelectionLength := goQuerySelection.Length()
 	if ok := (goQuerySelectionLength > 0); ok != true {
-		err(errors.New("goquery.Selection empty"))
-		return
+		return nil, errors.New("goquery.Selection empty")
 	}
 	steamChartPage := NewSteamChartPage(goQuerySelection)
-	if ok := len(steamChartPage.Name) > 0; ok != true {
-		err(errors.New("SteamChart.AppID negative"))
-		return
+	steamChartPage.AppID = parseSteamAppID(URL)
+	steamChartPage.URL = URL
+	if ok := steamChartPage.AppID > -1; ok != true {
+		return nil, errors.New("SteamChart.AppID negative")
 	}
-	success(steamChartPage)
+	return steamChartPage, nil
 }
 
 func scrapeSteamChartGameDelta(s *goquery.Selection) time.Time {
@@ -116,6 +125,10 @@ func scrapeSteamChartGamePlayerPeakDelta(s *goquery.Selection) int {
 	return n
 }
 
+func scrapeSteamChartGameTitle(s *goquery.Selection) string {
+	return strings.TrimSpace(s.Find("#app-title").First().Text())
+}
+
 func writeSteamChartPage(fullpath string, s *SteamChartPage) error {
 	err := os.MkdirAll(fullpath, os.ModePerm)
 	if err != nil {
@@ -128,18 +141,8 @@ func writeSteamChartPage(fullpath string, s *SteamChartPage) error {
 	if err != nil {
 		return err
 	}
-	filename := fmt.Sprintf("chart-result-%s.json", strings.ToLower(s.Name))
+	filename := fmt.Sprintf("chart-result-%d.json", s.AppID)
 	fullname := filepath.Join(fullpath, filename)
 	err = ioutil.WriteFile(fullname, b, os.ModePerm)
 	return err
 }
-
-func writeSteamChartPageDefault(s *SteamChartPage) error {
-	user, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-	fullpath := filepath.Join(user.HomeDir, "Desktop", "steambot", "games", s.Name)
-	err = writeSteamChartPage(fullpath, s)
-	return err
-}
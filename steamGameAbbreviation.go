@@ -1,14 +1,13 @@
-package main
+package steamer
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"net/url"
 	"os"
-	"os/user"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -19,39 +18,59 @@ import (
 )
 
 type SteamGameAbbreviation struct {
-	AppID     int       `json:"app_ID"`
-	BundleID  int       `json:"bundle_ID"`
-	CrtrID    []int     `json:"crtr_ID"`
-	DescID    []int     `json:"desc_ID"`
-	Name      string    `json:"name"`
-	PackageID int       `json:"package_ID"`
-	TagID     []int     `json:"tag_ID"`
-	Timestamp time.Time `json:"timestamp"`
-	URL       string    `json:"URL"`
+	AppID              int                          `json:"app_ID"`
+	BundleID           int                          `json:"bundle_ID"`
+	CrtrID             []int                        `json:"crtr_ID"`
+	DescID             []int                        `json:"desc_ID"`
+	DiscountPercentage int                          `json:"discount_percentage"`
+	Name               string                       `json:"name"`
+	PackageID          int                          `json:"package_ID"`
+	Platforms          []string                     `json:"platforms"`
+	PriceBase          int                          `json:"price_base"`
+	PriceFinal         int                          `json:"price_final"`
+	PriceText          string                       `json:"price_text"`
+	ReleaseDate        time.Time                    `json:"release_date"`
+	ReleaseDateText    string                       `json:"release_date_text"`
+	Reviews            SteamPageGameAggregateReview `json:"reviews"`
+	TagID              []int                        `json:"tag_ID"`
+	Timestamp          time.Time                    `json:"timestamp"`
+	Title              string                       `json:"title"`
+	URL                string                       `json:"URL"`
 }
 
 func NewSteamGameAbbreviation(s *goquery.Selection) *SteamGameAbbreviation {
-	return &SteamGameAbbreviation{
-		AppID:     scrapeSteamAbbreviationAppID(s),
-		BundleID:  scrapeSteamAbbreviationBundleID(s),
-		CrtrID:    scrapeSteamAbbreviationCrtrID(s),
-		DescID:    scrapeSteamAbbreviationDescID(s),
-		Name:      scrapeSteamAbbreviationName(s),
-		PackageID: scrapeSteamAbbreviationPackageID(s),
-		TagID:     scrapeSteamAbbreviationTagID(s),
-		Timestamp: time.Now(),
-		URL:       s.AttrOr("href", "NIL")}
-}
-
-func onGetSteamGameAbbreviation(c *http.Client, URL string, revisit bool, snap func(s *Snapshot), success func(s *SteamGameAbbreviation), err func(e error)) {
-	if revisit == false {
-		if u, err := url.Parse(URL); err == nil {
-			if ok, _ := hasVisitedURLDefault(u); ok {
-				return
-			}
-		}
+	releaseDateText := scrapeSteamAbbreviationReleaseDateText(s)
+	steamGameAbbreviation := &SteamGameAbbreviation{
+		AppID:              scrapeSteamAbbreviationAppID(s),
+		BundleID:           scrapeSteamAbbreviationBundleID(s),
+		CrtrID:             scrapeSteamAbbreviationCrtrID(s),
+		DescID:             scrapeSteamAbbreviationDescID(s),
+		DiscountPercentage: scrapeSteamAbbreviationDiscountPercentage(s),
+		Name:               scrapeSteamAbbreviationName(s),
+		PackageID:          scrapeSteamAbbreviationPackageID(s),
+		Platforms:          scrapeSteamAbbreviationPlatforms(s),
+		PriceBase:          scrapeSteamAbbreviationPriceBase(s),
+		PriceFinal:         scrapeSteamAbbreviationPriceFinal(s),
+		PriceText:          scrapeSteamAbbreviationPriceText(s),
+		ReleaseDate:        parseSteamReleaseDate(releaseDateText),
+		ReleaseDateText:    releaseDateText,
+		Reviews:            scrapeSteamAbbreviationReviews(s),
+		TagID:              scrapeSteamAbbreviationTagID(s),
+		Timestamp:          time.Now(),
+		Title:              scrapeSteamAbbreviationTitle(s),
+		URL:                s.AttrOr("href", "NIL")}
+	if steamGameAbbreviation.PriceBase == 0 {
+		steamGameAbbreviation.PriceBase = steamGameAbbreviation.PriceFinal
+	}
+	return steamGameAbbreviation
+}
+
+func (crawler *Crawler) onGetSteamGameAbbreviation(ctx context.Context, URL string, revisit bool, snap func(s *Snapshot), success func(s *SteamGameAbbreviation), skip func(s *SteamGameAbbreviation), err func(e error)) {
+	snapshot := crawler.snapshot(ctx, URL, revisit, nil)
+	if ok := (ctx.Err() != nil && snapshot.StatusCode == 0); ok {
+		err(ctx.Err())
+		return
 	}
-	snapshot := NewSnapshot(c, http.MethodGet, URL, nil)
 	snap(snapshot)
 	if ok := (snapshot.StatusCode == http.StatusOK); ok != true {
 		err(errors.New(snapshot.Status))
@@ -62,16 +81,16 @@ func onGetSteamGameAbbreviation(c *http.Client, URL string, revisit bool, snap f
 		return
 	}
 	CSSSelector := "a.search_result_row[href]"
-	goQuerySelection := snapshot.document.Find(CSSSelector)
-	goQuerySelectionLength := goQuerySelection.Length()
-	if ok := (goQuerySelectionLength > 0); ok != true {
-		err(errors.New("goquery.Selection empty"))
-		return
-	}
+	// a page without rows is past the last result; it is reported as zero games found rather than a failure
+	eachSteamGameAbbreviation(snapshot.document.Find(CSSSelector), success, skip)
+}
+
+func eachSteamGameAbbreviation(goQuerySelection *goquery.Selection, success func(s *SteamGameAbbreviation), skip func(s *SteamGameAbbreviation)) {
 	goQuerySelection.Each(func(j int, s *goquery.Selection) {
 		steamGameAbbreviation := NewSteamGameAbbreviation(s)
+		// bundles and packages list several apps (or none) and have no game page of their own
 		if ok := steamGameAbbreviation.AppID > -1; ok != true {
-			err(errors.New("SteamGameAbbreviation.AppID is negative"))
+			skip(steamGameAbbreviation)
 			return
 		}
 		success(steamGameAbbreviation)
@@ -79,7 +98,10 @@ func onGetSteamGameAbbreviation(c *http.Client, URL string, revisit bool, snap f
 }
 
 func scrapeSteamAbbreviationAppID(s *goquery.Selection) int {
-	ID, _ := strconv.Atoi(s.AttrOr("data-ds-appid", "-1"))
+	ID, err := strconv.Atoi(strings.TrimSpace(s.AttrOr("data-ds-appid", "-1")))
+	if err != nil {
+		return -1
+	}
 	return ID
 }
 
@@ -114,6 +136,21 @@ func scrapeSteamAbbreviationDescID(s *goquery.Selection) []int {
 	return descID
 }
 
+func scrapeSteamAbbreviationDiscountPercentage(s *goquery.Selection) int {
+	if x := s.Find("div.discount_block[data-discount]").First(); x.Length() != 0 {
+		n, err := strconv.Atoi(x.AttrOr("data-discount", "0"))
+		if err == nil {
+			return n
+		}
+	}
+	substring := regexp.MustCompile(`[^0-9]`).ReplaceAllString(s.Find("div.discount_pct, div.search_discount span").First().Text(), "")
+	n, err := strconv.Atoi(substring)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
 func scrapeSteamAbbreviationName(s *goquery.Selection) string {
 	return regexp.MustCompile(`[^a-zA-Z0-9]`).ReplaceAllString(strings.TrimSpace(s.Find(".title").Text()), "")
 }
@@ -123,6 +160,77 @@ func scrapeSteamAbbreviationPackageID(s *goquery.Selection) int {
 	return ID
 }
 
+func scrapeSteamAbbreviationPlatforms(s *goquery.Selection) []string {
+	platforms := []string{}
+	s.Find("span.platform_img").Each(func(i int, s *goquery.Selection) {
+		for _, class := range strings.Fields(s.AttrOr("class", "")) {
+			if class != "platform_img" {
+				platforms = append(platforms, class)
+			}
+		}
+	})
+	if s.Find("span.vr_supported, span.vr_required").Length() > 0 {
+		platforms = append(platforms, "vr")
+	}
+	return platforms
+}
+
+func scrapeSteamAbbreviationPriceBase(s *goquery.Selection) int {
+	x := s.Find("div.discount_original_price, div.search_price strike").First()
+	if x.Length() == 0 {
+		return 0
+	}
+	return parseSteamPurchaseOptionPrice(x.Text())
+}
+
+func scrapeSteamAbbreviationPriceFinal(s *goquery.Selection) int {
+	x := s.Find("[data-price-final]").First()
+	if x.Length() != 0 {
+		n, err := strconv.Atoi(x.AttrOr("data-price-final", "0"))
+		if err == nil {
+			return n
+		}
+	}
+	x = s.Find("div.discount_final_price").First()
+	if x.Length() != 0 {
+		return parseSteamPurchaseOptionPrice(x.Text())
+	}
+	x = s.Find("div.search_price").First().Clone()
+	x.Find("strike").Remove()
+	return parseSteamPurchaseOptionPrice(x.Text())
+}
+
+func scrapeSteamAbbreviationPriceText(s *goquery.Selection) string {
+	// the final price as shown (e.g. "$9.99", "Free to Play"), empty when the row is not for sale
+	x := s.Find("div.discount_final_price").First()
+	if x.Length() == 0 {
+		x = s.Find("div.search_price").First().Clone()
+		x.Find("strike").Remove()
+	}
+	return strings.Join(strings.Fields(x.Text()), " ")
+}
+
+func scrapeSteamAbbreviationReleaseDateText(s *goquery.Selection) string {
+	return strings.TrimSpace(s.Find("div.search_released").First().Text())
+}
+
+func scrapeSteamAbbreviationReviews(s *goquery.Selection) SteamPageGameAggregateReview {
+	var steamPageGameAggregateReview SteamPageGameAggregateReview
+	// {data-tooltip-html: "Very Positive<br>88% of the 7,000 user reviews for this game are positive."}
+	p := strings.SplitN(s.Find("span.search_review_summary").First().AttrOr("data-tooltip-html", ""), "<br>", 2)
+	steamPageGameAggregateReview.Sentiment = strings.TrimSpace(p[0])
+	if len(p) != 2 {
+		return steamPageGameAggregateReview
+	}
+	if substring := regexp.MustCompile(`(\d+)%`).FindStringSubmatch(p[1]); len(substring) == 2 {
+		steamPageGameAggregateReview.Percentage, _ = strconv.Atoi(substring[1])
+	}
+	if substring := regexp.MustCompile(`([\d,.]+)\s+user review`).FindStringSubmatch(p[1]); len(substring) == 2 {
+		steamPageGameAggregateReview.Count, _ = strconv.Atoi(regexp.MustCompile(`[^0-9]`).ReplaceAllString(substring[1], ""))
+	}
+	return steamPageGameAggregateReview
+}
+
 func scrapeSteamAbbreviationTagID(s *goquery.Selection) []int {
 	var tagID []int
 	tID := s.AttrOr("data-ds-tagids", "[]")
@@ -136,6 +244,10 @@ func scrapeSteamAbbreviationTagID(s *goquery.Selection) []int {
 	return tagID
 }
 
+func scrapeSteamAbbreviationTitle(s *goquery.Selection) string {
+	return strings.TrimSpace(s.Find(".title").First().Text())
+}
+
 func writeSteamGameAbbreviation(fullpath string, s *SteamGameAbbreviation) error {
 	err := os.MkdirAll(fullpath, os.ModePerm)
 	if err != nil {
@@ -145,18 +257,8 @@ func writeSteamGameAbbreviation(fullpath string, s *SteamGameAbbreviation) error
 	if err != nil {
 		return err
 	}
-	filename := fmt.Sprintf("search-result-%s.json", strings.ToLower(s.Name))
+	filename := fmt.Sprintf("search-result-%d.json", s.AppID)
 	fullname := filepath.Join(fullpath, filename)
 	err = ioutil.WriteFile(fullname, b, os.ModePerm)
 	return err
 }
-
-func writeSteamGameAbbreviationDefault(s *SteamGameAbbreviation) error {
-	user, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-	fullpath := filepath.Join(user.HomeDir, "Desktop", "steambot", "games", s.Name)
-	err = writeSteamGameAbbreviation(fullpath, s)
-	return err
-}
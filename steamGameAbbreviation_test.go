@@ -0,0 +1,35 @@
+package steamer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestEachSteamGameAbbreviation(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><body>
+<a class="search_result_row" href="https://store.steampowered.com/app/10/" data-ds-appid="10"></a>
+<a class="search_result_row" href="https://store.steampowered.com/bundle/1/" data-ds-bundleid="1"></a>
+<a class="search_result_row" href="https://store.steampowered.com/sub/2/" data-ds-packageid="2" data-ds-appid="10,20"></a>
+<a class="search_result_row" href="https://store.steampowered.com/sub/3/" data-ds-packageid="3" data-ds-appid="30"></a>
+</body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var appIDs []int
+	var skipped []string
+	eachSteamGameAbbreviation(doc.Find("a.search_result_row[href]"),
+		func(s *SteamGameAbbreviation) {
+			appIDs = append(appIDs, s.AppID)
+		},
+		func(s *SteamGameAbbreviation) {
+			skipped = append(skipped, s.URL)
+		})
+	if len(appIDs) != 2 || appIDs[0] != 10 || appIDs[1] != 30 {
+		t.Errorf("app IDs = %v, want [10 30]", appIDs)
+	}
+	if len(skipped) != 2 {
+		t.Errorf("skipped = %v, want the bundle and the two-app package", skipped)
+	}
+}
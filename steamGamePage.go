@@ -1,14 +1,13 @@
-package main
+package steamer
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"net/url"
 	"os"
-	"os/user"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -19,44 +18,60 @@ import (
 )
 
 type SteamGamePage struct {
-	AppID                   int                          `json:"app_ID"`
-	Available               bool                         `json:"available"`
-	Categories              []SteamPageGameCategory      `json:"categories"`
-	ComingSoon              bool                         `json:"coming_soon"`
-	Description             string                       `json:"description"`
-	Developers              []SteamPageGameDeveloper     `json:"developers"`
-	EarlyAccess             bool                         `json:"early_access"`
-	Genres                  []SteamPageGameGenre         `json:"genres"`
-	Languages               []SteamPageGameLanguage      `json:"languages"`
-	Name                    string                       `json:"name"`
-	Publishers              []SteamPageGamePublisher     `json:"publishers"`
-	ReleaseDate             time.Time                    `json:"release_date"`
-	RequirementsMinimum     []SteamPageGameRequirement   `json:"requirements_minimum"`
-	RequirementsRecommended []SteamPageGameRequirement   `json:"requirements_recommended"`
-	ReviewsAll              SteamPageGameAggregateReview `json:"reviews_all"`
-	ReviewsRecent           SteamPageGameAggregateReview `json:"reviews_recent"`
-	SocialMedia             []SteamGameSocialMedia       `json:"social_media"`
-	Tags                    []SteamPageGameTag           `json:"tags"`
-	Title                   string                       `json:"title"`
-	Timestamp               time.Time                    `json:"timestamp"`
-	URL                     string                       `json:"URL"`
-	Verbose                 string                       `json:"verbose"`
-	Website                 string                       `json:"website"`
+	AppID                   int                           `json:"app_ID"`
+	Available               bool                          `json:"available"`
+	Categories              []SteamPageGameCategory       `json:"categories"`
+	ComingSoon              bool                          `json:"coming_soon"`
+	Currency                string                        `json:"currency"`
+	Description             string                        `json:"description"`
+	Developers              []SteamPageGameDeveloper      `json:"developers"`
+	DiscountEnd             time.Time                     `json:"discount_end"`
+	DiscountPercentage      int                           `json:"discount_percentage"`
+	EarlyAccess             bool                          `json:"early_access"`
+	Free                    bool                          `json:"free"`
+	Genres                  []SteamPageGameGenre          `json:"genres"`
+	Languages               []SteamPageGameLanguage       `json:"languages"`
+	Name                    string                        `json:"name"`
+	PriceBase               int                           `json:"price_base"`
+	PriceFinal              int                           `json:"price_final"`
+	Publishers              []SteamPageGamePublisher      `json:"publishers"`
+	PurchaseOptions         []SteamPageGamePurchaseOption `json:"purchase_options"`
+	ReleaseDate             time.Time                     `json:"release_date"`
+	RequirementsMinimum     []SteamPageGameRequirement    `json:"requirements_minimum"`
+	RequirementsRecommended []SteamPageGameRequirement    `json:"requirements_recommended"`
+	ReviewsAll              SteamPageGameAggregateReview  `json:"reviews_all"`
+	ReviewsRecent           SteamPageGameAggregateReview  `json:"reviews_recent"`
+	SocialMedia             []SteamGameSocialMedia        `json:"social_media"`
+	Tags                    []SteamPageGameTag            `json:"tags"`
+	Title                   string                        `json:"title"`
+	Timestamp               time.Time                     `json:"timestamp"`
+	URL                     string                        `json:"URL"`
+	Verbose                 string                        `json:"verbose"`
+	Website                 string                        `json:"website"`
 }
 
 func NewSteamGamePage(s *goquery.Selection) *SteamGamePage {
+	purchaseOptions := scrapeSteamGamePurchaseOptions(s)
+	purchaseOption := parseSteamGamePurchaseOption(purchaseOptions)
 	return &SteamGamePage{
 		AppID:                   scrapeSteamGameAppID(s),
 		Available:               scrapeSteamGameAvailable(s),
 		Categories:              scrapeSteamGameCategories(s),
 		ComingSoon:              scrapeSteamGameComingSoon(s),
+		Currency:                scrapeSteamGameCurrency(s),
 		Description:             scrapeSteamGameDescription(s),
 		Developers:              scrapeSteamGameDevelopers(s),
+		DiscountEnd:             purchaseOption.DiscountEnd,
+		DiscountPercentage:      purchaseOption.DiscountPercentage,
 		EarlyAccess:             scrapeSteamGameEarlyAccess(s),
+		Free:                    purchaseOption.Free,
 		Genres:                  scrapeSteamGameGenres(s),
 		Languages:               scrapeSteamGameLanguages(s),
 		Name:                    scrapeSteamGameName(s),
+		PriceBase:               purchaseOption.PriceBase,
+		PriceFinal:              purchaseOption.PriceFinal,
 		Publishers:              scrapeSteamGamePublishers(s),
+		PurchaseOptions:         purchaseOptions,
 		ReleaseDate:             scrapeSteamGameReleaseDate(s),
 		RequirementsMinimum:     scrapeSteamGameRequirementsMinimum(s),
 		RequirementsRecommended: scrapeSteamGameRequirementsRecommended(s),
@@ -71,14 +86,7 @@ func NewSteamGamePage(s *goquery.Selection) *SteamGamePage {
 		Website:                 scrapeSteamGameWebsite(s)}
 }
 
-func onGetSteamGamePage(c *http.Client, URL string, revisit bool, snap func(s *Snapshot), success func(s *SteamGamePage), err func(e error)) {
-	if revisit == false {
-		if u, err := url.Parse(URL); err == nil {
-			if ok, _ := hasVisitedURLDefault(u); ok {
-				return
-			}
-		}
-	}
+func (crawler *Crawler) onGetSteamGamePage(ctx context.Context, URL string, revisit bool, snap func(s *Snapshot), success func(s *SteamGamePage), err func(e error)) {
 	lastAgeCheckCookie := &http.Cookie{
 		Domain:   "store.steampowered.com",
 		HttpOnly: false,
@@ -91,7 +99,11 @@ func onGetSteamGamePage(c *http.Client, URL string, revisit bool, snap func(s *S
 		Name:     "birthtime",
 		Path:     "/",
 		Value:    "-949485599"}
-	snapshot := NewSnapshot(c, http.MethodGet, URL, &[]*http.Cookie{birthtimeCookie, lastAgeCheckCookie})
+	snapshot := crawler.snapshot(ctx, URL, revisit, &[]*http.Cookie{birthtimeCookie, lastAgeCheckCookie})
+	if ok := (ctx.Err() != nil && snapshot.StatusCode == 0); ok {
+		err(ctx.Err())
+		return
+	}
 	snap(snapshot)
 	if ok := (snapshot.StatusCode == http.StatusOK); ok != true {
 		err(errors.New(snapshot.Status))
@@ -101,19 +113,55 @@ func onGetSteamGamePage(c *http.Client, URL string, revisit bool, snap func(s *S
 		err(snapshot.ErrDoc)
 		return
 	}
+	steamGamePage, e := newSteamGamePageFromDocument(URL, snapshot.document)
+	if e != nil {
+		err(e)
+		return
+	}
+	success(steamGamePage)
+}
+
+func newSteamGamePageFromDocument(URL string, doc *goquery.Document) (*SteamGamePage, error) {
 	CSSSelector := "html"
-	goQuerySelection := snapshot.document.Find(CSSSelector)
+	goQuerySelection := doc.Find(CSSSelector)
 	goQuerySelectionLength := goQuerySelection.Length()
 	if ok := (goQuerySelectionLength > 0); ok != true {
-		err(errors.New("goquery.Selection empty"))
-		return
+		return nil, errors.New("goquery.Selection empty")
 	}
 	steamGamePage := NewSteamGamePage(goQuerySelection)
 	if ok := steamGamePage.AppID > -1; ok != true {
-		err(errors.New("SteamGamePage.AppID negative"))
-		return
+		steamGamePage.AppID = parseSteamAppID(URL)
 	}
-	success(steamGamePage)
+	if ok := steamGamePage.AppID > -1; ok != true {
+		return nil, errors.New("SteamGamePage.AppID negative")
+	}
+	return steamGamePage, nil
+}
+
+func parseSteamAppID(URL string) int {
+	substring := regexp.MustCompile(`/app/(\d+)`).FindStringSubmatch(URL)
+	if len(substring) != 2 {
+		return -1
+	}
+	ID, err := strconv.Atoi(substring[1])
+	if err != nil {
+		return -1
+	}
+	return ID
+}
+
+func parseSteamGamePurchaseOption(s []SteamPageGamePurchaseOption) SteamPageGamePurchaseOption {
+	for _, x := range s {
+		if x.BundleID < 0 && x.Subscription != true {
+			return x
+		}
+	}
+	if len(s) > 0 {
+		return s[0]
+	}
+	return SteamPageGamePurchaseOption{
+		BundleID:  -1,
+		PackageID: -1}
 }
 
 func scrapeSteamGameAppID(s *goquery.Selection) int {
@@ -139,6 +187,10 @@ func scrapeSteamGameComingSoon(s *goquery.Selection) bool {
 	return ok
 }
 
+func scrapeSteamGameCurrency(s *goquery.Selection) string {
+	return strings.TrimSpace(s.Find("meta[itemprop='priceCurrency']").First().AttrOr("content", ""))
+}
+
 func scrapeSteamGameDescription(s *goquery.Selection) string {
 	return strings.TrimSpace(s.Find("div.game_description_snippet").Text())
 }
@@ -188,11 +240,18 @@ func scrapeSteamGamePublishers(s *goquery.Selection) []SteamPageGamePublisher {
 	return steamPageGamePublishers
 }
 
-func scrapeSteamGameReleaseDate(s *goquery.Selection) time.Time {
-	substring := strings.TrimSpace(s.Find("div.release_date div.date").First().Text())
-	substring = strings.ReplaceAll(substring, ",", "")
+func scrapeSteamGamePurchaseOptions(s *goquery.Selection) []SteamPageGamePurchaseOption {
+	var steamPageGamePurchaseOptions []SteamPageGamePurchaseOption
+	s.Find("div.game_area_purchase_game").Each(func(i int, s *goquery.Selection) {
+		steamPageGamePurchaseOptions = append(steamPageGamePurchaseOptions, NewSteamPageGamePurchaseOption(s))
+	})
+	return steamPageGamePurchaseOptions
+}
+
+func parseSteamReleaseDate(substring string) time.Time {
+	substring = strings.ReplaceAll(strings.TrimSpace(substring), ",", "")
 	p := strings.Split(substring, " ")
-	if ok := len(p) == 3; ok != true {
+	if ok := (len(p) == 3 && len(p[2]) == 4); ok != true {
 		return time.Time{}
 	}
 	day := p[0]
@@ -209,6 +268,10 @@ func scrapeSteamGameReleaseDate(s *goquery.Selection) time.Time {
 	return t
 }
 
+func scrapeSteamGameReleaseDate(s *goquery.Selection) time.Time {
+	return parseSteamReleaseDate(s.Find("div.release_date div.date").First().Text())
+}
+
 func scrapeSteamGameRequirementsMinimum(s *goquery.Selection) []SteamPageGameRequirement {
 	var steamPageGameRequirements []SteamPageGameRequirement
 	s.Find("div.game_area_sys_req").Each(func(i int, s *goquery.Selection) {
@@ -328,18 +391,8 @@ func writeSteamGamePage(fullpath string, s *SteamGamePage) error {
 	if err != nil {
 		return err
 	}
-	filename := fmt.Sprintf("page-result-%s.json", strings.ToLower(s.Name))
+	filename := fmt.Sprintf("page-result-%d.json", s.AppID)
 	fullname := filepath.Join(fullpath, filename)
 	err = ioutil.WriteFile(fullname, b, os.ModePerm)
 	return err
 }
-
-func writeSteamGamePageDefault(s *SteamGamePage) error {
-	user, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-	fullpath := filepath.Join(user.HomeDir, "Desktop", "steambot", "games", s.Name)
-	err = writeSteamGamePage(fullpath, s)
-	return err
-}
@@ -1,11 +1,10 @@
-package main
+package steamer
 
 import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/user"
 	"path/filepath"
 	"strings"
 	"time"
@@ -16,6 +15,7 @@ import (
 )
 
 type SteamGameSummary struct {
+	AppID                  int       `json:"app_ID"`
 	Available              bool      `json:"available"`
 	AverageDecline         int       `json:"average_decline"`
 	AverageGain            int       `json:"average_gain"`
@@ -24,8 +24,12 @@ type SteamGameSummary struct {
 	AveragePlayerCount     int       `json:"average_player_count"`
 	Categories             []string  `json:"categories"`
 	ComingSoon             bool      `json:"coming_soon"`
+	Currency               string    `json:"currency"`
 	Developers             []string  `json:"developers"`
+	DiscountEnd            time.Time `json:"discount_end"`
+	DiscountPercentage     int       `json:"discount_percentage"`
 	EarlyAccess            bool      `json:"early_access"`
+	Free                   bool      `json:"free"`
 	Genres                 []string  `json:"genres"`
 	Name                   string    `json:"name"`
 	MonthsSinceRelease     int       `json:"months_since_release"`
@@ -33,7 +37,10 @@ type SteamGameSummary struct {
 	PeakPlayersDate        string    `json:"peak_players_date"`
 	PlayerPeak24Hour       int       `json:"player_peak_24_hour"`
 	PlayerPeakAll          int       `json:"player_peak_all"`
+	PriceBase              int       `json:"price_base"`
+	PriceFinal             int       `json:"price_final"`
 	Publishers             []string  `json:"publishers"`
+	PurchaseOptions        []string  `json:"purchase_options"`
 	ReleaseDate            time.Time `json:"release_date"`
 	ReviewsAllCount        int       `json:"reviews_all_count"`
 	ReviewsAllSentiment    string    `json:"reviews_all_sentiment"`
@@ -51,8 +58,12 @@ type SteamGameSummary struct {
 }
 
 func NewSteamGameSummary(steamGamePage *SteamGamePage, steamChartPage *SteamChartPage) *SteamGameSummary {
+	if steamChartPage == nil {
+		steamChartPage = &SteamChartPage{}
+	}
 	steamGameSummaryStatistics := NewSteamGameSummaryStatistics(steamChartPage)
 	return &SteamGameSummary{
+		AppID:                  steamGamePage.AppID,
 		Available:              steamGamePage.Available,
 		AverageDecline:         steamGameSummaryStatistics.AverageDecline,
 		AverageGain:            steamGameSummaryStatistics.AverageGain,
@@ -61,8 +72,12 @@ func NewSteamGameSummary(steamGamePage *SteamGamePage, steamChartPage *SteamChar
 		AveragePlayerCount:     steamGameSummaryStatistics.AveragePlayerCount,
 		Categories:             parseSteamGameSummaryCategories(&steamGamePage.Categories),
 		ComingSoon:             steamGamePage.ComingSoon,
+		Currency:               steamGamePage.Currency,
 		Developers:             parseSteamGameSummaryDevelopers(&steamGamePage.Developers),
+		DiscountEnd:            steamGamePage.DiscountEnd,
+		DiscountPercentage:     steamGamePage.DiscountPercentage,
 		EarlyAccess:            steamGamePage.EarlyAccess,
+		Free:                   steamGamePage.Free,
 		Genres:                 parseSteamGameSummaryGenres(&steamGamePage.Genres),
 		Name:                   steamGamePage.Name,
 		MonthsSinceRelease:     steamGameSummaryStatistics.MonthsSinceRelease,
@@ -70,7 +85,10 @@ func NewSteamGameSummary(steamGamePage *SteamGamePage, steamChartPage *SteamChar
 		PeakPlayersDate:        steamGameSummaryStatistics.PeakPlayersDate,
 		PlayerPeak24Hour:       steamChartPage.PlayerPeak24Hour,
 		PlayerPeakAll:          steamChartPage.PlayerPeakAll,
+		PriceBase:              steamGamePage.PriceBase,
+		PriceFinal:             steamGamePage.PriceFinal,
 		Publishers:             parseSteamGameSummaryPublishers(&steamGamePage.Publishers),
+		PurchaseOptions:        parseSteamGameSummaryPurchaseOptions(&steamGamePage.PurchaseOptions),
 		ReleaseDate:            steamGamePage.ReleaseDate,
 		ReviewsAllCount:        steamGamePage.ReviewsAll.Count,
 		ReviewsAllSentiment:    steamGamePage.ReviewsAll.Sentiment,
@@ -87,6 +105,30 @@ func NewSteamGameSummary(steamGamePage *SteamGamePage, steamChartPage *SteamChar
 		YearsSinceRelease:      steamGameSummaryStatistics.YearsSinceRelease}
 }
 
+func NewSteamGameAbbreviationSummary(s *SteamGameAbbreviation) *SteamGameSummary {
+	return &SteamGameSummary{
+		AppID:               s.AppID,
+		Available:           (len(s.PriceText) > 0),
+		Categories:          []string{},
+		Developers:          []string{},
+		DiscountPercentage:  s.DiscountPercentage,
+		Free:                strings.HasPrefix(strings.ToLower(s.PriceText), "free"),
+		Genres:              []string{},
+		Name:                s.Name,
+		PriceBase:           s.PriceBase,
+		PriceFinal:          s.PriceFinal,
+		Publishers:          []string{},
+		PurchaseOptions:     []string{},
+		ReleaseDate:         s.ReleaseDate,
+		ReviewsAllCount:     s.Reviews.Count,
+		ReviewsAllSentiment: s.Reviews.Sentiment,
+		SocialMedia:         []string{},
+		Tags:                []string{},
+		Timestamp:           time.Now(),
+		Title:               parseSteamGameSummaryTitle(s.Title),
+		URL:                 s.URL}
+}
+
 func parseSteamGameSummaryCategories(s *[]SteamPageGameCategory) []string {
 	v := *s
 	categories := make([]string, len(v))
@@ -123,6 +165,15 @@ func parseSteamGameSummaryPublishers(s *[]SteamPageGamePublisher) []string {
 	return publishers
 }
 
+func parseSteamGameSummaryPurchaseOptions(s *[]SteamPageGamePurchaseOption) []string {
+	v := *s
+	purchaseOptions := make([]string, len(v))
+	for i, p := range v {
+		purchaseOptions[i] = p.Name
+	}
+	return purchaseOptions
+}
+
 func parseSteamGameSummarySocialMedia(s *[]SteamGameSocialMedia) []string {
 	v := *s
 	social := make([]string, len(v))
@@ -159,18 +210,8 @@ func writeSteamGameSummary(fullpath string, s *SteamGameSummary) error {
 	if err != nil {
 		return err
 	}
-	filename := fmt.Sprintf("summary-%s.json", strings.ToLower(s.Name))
+	filename := fmt.Sprintf("summary-%d.json", s.AppID)
 	fullname := filepath.Join(fullpath, filename)
 	err = ioutil.WriteFile(fullname, b, os.ModePerm)
 	return err
 }
-
-func writeSteamGameSummaryDefault(s *SteamGameSummary) error {
-	user, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-	fullpath := filepath.Join(user.HomeDir, "Desktop", "steambot", "games", s.Name)
-	err = writeSteamGameSummary(fullpath, s)
-	return err
-}
@@ -1,4 +1,4 @@
-package main
+package steamer
 
 type SteamGameSummaryStatistics struct {
 	AverageDecline        int
@@ -55,6 +55,8 @@ func NewSteamGameSummaryStatistics(s *SteamChartPage) SteamGameSummaryStatistics
 		averageMinPlayerCount = averageMinPlayerCount / monthsSinceRelease
 		averagePlayerCount = averagePlayerCount / monthsSinceRelease
 		yearsSinceRelease = monthsSinceRelease / 12
+	} else {
+		troughPlayers = 0
 	}
 	return SteamGameSummaryStatistics{
 		AverageDecline:        averageDecline,
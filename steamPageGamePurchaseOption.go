@@ -0,0 +1,138 @@
+package steamer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type SteamPageGamePurchaseOption struct {
+	BundleID           int       `json:"bundle_ID"`
+	DiscountEnd        time.Time `json:"discount_end"`
+	DiscountPercentage int       `json:"discount_percentage"` // {DiscountPercentage: 50}
+	Free               bool      `json:"free"`
+	Name               string    `json:"name"`
+	PackageID          int       `json:"package_ID"`
+	PriceBase          int       `json:"price_base"`  // {PriceBase: 2999} for $29.99
+	PriceFinal         int       `json:"price_final"` // {PriceFinal: 1499} for $14.99
+	Subscription       bool      `json:"subscription"`
+}
+
+func NewSteamPageGamePurchaseOption(s *goquery.Selection) SteamPageGamePurchaseOption {
+	steamPageGamePurchaseOption := SteamPageGamePurchaseOption{
+		BundleID:           scrapeSteamPurchaseOptionBundleID(s),
+		DiscountEnd:        scrapeSteamPurchaseOptionDiscountEnd(s),
+		DiscountPercentage: scrapeSteamPurchaseOptionDiscountPercentage(s),
+		Free:               scrapeSteamPurchaseOptionFree(s),
+		Name:               scrapeSteamPurchaseOptionName(s),
+		PackageID:          scrapeSteamPurchaseOptionPackageID(s),
+		PriceBase:          scrapeSteamPurchaseOptionPriceBase(s),
+		PriceFinal:         scrapeSteamPurchaseOptionPriceFinal(s),
+		Subscription:       scrapeSteamPurchaseOptionSubscription(s)}
+	if steamPageGamePurchaseOption.PriceBase == 0 {
+		steamPageGamePurchaseOption.PriceBase = steamPageGamePurchaseOption.PriceFinal
+	}
+	return steamPageGamePurchaseOption
+}
+
+func parseSteamPurchaseOptionPrice(s string) int {
+	s = strings.TrimSpace(s)
+	// prices without a two digit fraction (e.g. "¥ 1,980") are scaled to match data-price-final
+	fraction := regexp.MustCompile(`[.,]\d{2}(\D*)$`).MatchString(s)
+	substring := regexp.MustCompile(`[^0-9]`).ReplaceAllString(s, "")
+	n, err := strconv.Atoi(substring)
+	if err != nil {
+		return 0
+	}
+	if fraction != true {
+		n = n * 100
+	}
+	return n
+}
+
+func scrapeSteamPurchaseOptionBundleID(s *goquery.Selection) int {
+	ID, _ := strconv.Atoi(s.Find("input[name='bundleid']").First().AttrOr("value", "-1"))
+	return ID
+}
+
+func scrapeSteamPurchaseOptionDiscountEnd(s *goquery.Selection) time.Time {
+	substring := regexp.MustCompile(`DiscountCountdown,\s*(\d+)`).FindStringSubmatch(s.Find("script").Text())
+	if len(substring) == 2 {
+		n, err := strconv.ParseInt(substring[1], 10, 64)
+		if err == nil {
+			return time.Unix(n, 0).UTC()
+		}
+	}
+	return parseSteamPurchaseOptionDiscountEnd(s.Find("p.game_purchase_discount_countdown").Text(), time.Now())
+}
+
+func parseSteamPurchaseOptionDiscountEnd(s string, now time.Time) time.Time {
+	// the countdown follows the store locale, e.g. "Offer ends 21 October" or "Offer ends October 21"
+	substring := regexp.MustCompile(`(?i)ends\s+(\d{1,2}\s+[A-Za-z]+|[A-Za-z]+\s+\d{1,2})\b`).FindStringSubmatch(s)
+	if len(substring) != 2 {
+		return time.Time{}
+	}
+	value := fmt.Sprintf("%s %d", strings.Join(strings.Fields(substring[1]), " "), now.Year())
+	for _, layout := range []string{"2 January 2006", "January 2 2006", "2 Jan 2006", "Jan 2 2006"} {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		// the countdown omits the year, so a date already behind today falls in the next one
+		if t.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)) {
+			t = t.AddDate(1, 0, 0)
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func scrapeSteamPurchaseOptionDiscountPercentage(s *goquery.Selection) int {
+	n, err := strconv.Atoi(s.Find("div.discount_block").First().AttrOr("data-discount", "0"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func scrapeSteamPurchaseOptionFree(s *goquery.Selection) bool {
+	substring := strings.ToLower(strings.TrimSpace(s.Find("div.game_purchase_price").First().Text()))
+	return strings.HasPrefix(substring, "free")
+}
+
+func scrapeSteamPurchaseOptionName(s *goquery.Selection) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s.Find("h1").First().Text()), "Buy "))
+}
+
+func scrapeSteamPurchaseOptionPackageID(s *goquery.Selection) int {
+	ID, _ := strconv.Atoi(s.Find("input[name='subid']").First().AttrOr("value", "-1"))
+	return ID
+}
+
+func scrapeSteamPurchaseOptionPriceBase(s *goquery.Selection) int {
+	x := s.Find("div.discount_original_price").First()
+	if x.Length() == 0 {
+		return 0
+	}
+	return parseSteamPurchaseOptionPrice(x.Text())
+}
+
+func scrapeSteamPurchaseOptionPriceFinal(s *goquery.Selection) int {
+	x := s.Find("[data-price-final]").First()
+	if x.Length() != 0 {
+		n, err := strconv.Atoi(x.AttrOr("data-price-final", "0"))
+		if err == nil {
+			return n
+		}
+	}
+	x = s.Find("div.discount_final_price, div.game_purchase_price").First()
+	return parseSteamPurchaseOptionPrice(x.Text())
+}
+
+func scrapeSteamPurchaseOptionSubscription(s *goquery.Selection) bool {
+	return s.HasClass("game_area_purchase_game_dropdown_subscription") || (s.Find(".game_purchase_subscription, .game_area_purchase_game_dropdown_subscription").Length() > 0)
+}
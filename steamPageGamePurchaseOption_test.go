@@ -0,0 +1,50 @@
+package steamer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSteamPurchaseOptionPrice(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"$9.99", 999},
+		{"  $19.99 USD  ", 1999},
+		{"9,99€", 999},
+		{"1.234,56 €", 123456},
+		{"R$ 29,90", 2990},
+		{"¥ 1,980", 198000},
+		{"₩ 22,000", 2200000},
+		{"Free", 0},
+		{"Free to Play", 0},
+		{"", 0},
+	}
+	for _, test := range tests {
+		if got := parseSteamPurchaseOptionPrice(test.s); got != test.want {
+			t.Errorf("parseSteamPurchaseOptionPrice(%q) = %d, want %d", test.s, got, test.want)
+		}
+	}
+}
+
+func TestParseSteamPurchaseOptionDiscountEnd(t *testing.T) {
+	now := time.Date(2026, 12, 30, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		s    string
+		want time.Time
+	}{
+		{"Offer ends 2 January", time.Date(2027, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Offer ends January 2", time.Date(2027, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Offer ends Jan 2", time.Date(2027, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"SPECIAL PROMOTION! Offer ends 31 December", time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"Offer ends 30 December", time.Date(2026, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{"Offer ends in 3 days", time.Time{}},
+		{"", time.Time{}},
+	}
+	for _, test := range tests {
+		if got := parseSteamPurchaseOptionDiscountEnd(test.s, now); got.Equal(test.want) != true {
+			t.Errorf("parseSteamPurchaseOptionDiscountEnd(%q) = %v, want %v", test.s, got, test.want)
+		}
+	}
+}
@@ -1,13 +1,16 @@
-package main
+package steamer
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
-	"os/user"
 	"path/filepath"
 	"strings"
 	"time"
@@ -16,38 +19,105 @@ import (
 )
 
 type Snapshot struct {
+	body         []byte
 	document     *goquery.Document
 	request      *http.Request
 	response     *http.Response
-	ErrDoc       error         `json:"err_document"`
-	ErrRes       error         `json:"err_response"`
-	ErrReq       error         `json:"err_request"`
-	Method       string        `json:"method"`
-	RequestOK    bool          `json:"request_OK"`
-	ResponseOK   bool          `json:"response_OK"`
-	Status       string        `json:"status"`
-	StatusCode   int           `json:"status_code"`
-	TimeDuration time.Duration `json:"time_duration"`
-	TimeEnd      time.Time     `json:"time_end"`
-	TimeStart    time.Time     `json:"time_start"`
-	URL          string        `json:"URL"`
+	Attempts     []SnapshotAttempt `json:"attempts"`
+	BodyHash     string            `json:"body_hash"`
+	Cache        string            `json:"cache"`
+	ErrDoc       error             `json:"err_document"`
+	ErrRes       error             `json:"err_response"`
+	ErrReq       error             `json:"err_request"`
+	Method       string            `json:"method"`
+	RequestOK    bool              `json:"request_OK"`
+	ResponseOK   bool              `json:"response_OK"`
+	Status       string            `json:"status"`
+	StatusCode   int               `json:"status_code"`
+	TimeDuration time.Duration     `json:"time_duration"`
+	TimeEnd      time.Time         `json:"time_end"`
+	TimeStart    time.Time         `json:"time_start"`
+	URL          string            `json:"URL"`
 }
 
-func NewSnapshot(c *http.Client, HTTPMethod, URL string, HTTPCookies *[]*http.Cookie) *Snapshot {
+func NewSnapshot(ctx context.Context, c *http.Client, r *SnapshotRetry, HTTPMethod, URL string, HTTPCookies *[]*http.Cookie) *Snapshot {
 	ok := (strings.HasPrefix(URL, "http://") || strings.HasPrefix(URL, "https://"))
 	if ok != true {
 		URL = fmt.Sprintf("https://%s", URL)
 	}
-	req, errReq := http.NewRequest(HTTPMethod, URL, nil)
-	if HTTPCookies != nil && req != nil {
+	var (
+		attempts  []SnapshotAttempt
+		errReq    error
+		errRes    error
+		req       *http.Request
+		res       *http.Response
+		timeEnd   time.Time
+		timeStart time.Time
+	)
+	for i := 1; ; i++ {
+		if errReq = ctx.Err(); errReq != nil {
+			break
+		}
+		req, errReq = newSnapshotRequest(ctx, HTTPMethod, URL, HTTPCookies)
+		if errReq != nil {
+			break
+		}
+		timeStart = time.Now()
+		res, errRes = c.Do(req)
+		timeEnd = time.Now()
+		attempt := NewSnapshotAttempt(res, errRes, timeStart, timeEnd)
+		if ok := r.Retry(i, res, errRes); ok != true {
+			attempts = append(attempts, attempt)
+			break
+		}
+		attempt.Wait = r.Wait(i, attempt.RetryAfter)
+		attempts = append(attempts, attempt)
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+			// a cancel at the top of the next attempt must not hand back the closed response
+			res = nil
+		}
+		timer := time.NewTimer(attempt.Wait)
+		select {
+		case <-timer.C:
+			continue
+		case <-ctx.Done():
+			timer.Stop()
+		}
+		res, errRes = nil, ctx.Err()
+		break
+	}
+	snapshot := newSnapshot(HTTPMethod, URL, req, errReq, res, errRes, timeStart, timeEnd)
+	snapshot.Attempts = attempts
+	return snapshot
+}
+
+func GetSnapshot(ctx context.Context, c *http.Client, r *SnapshotRetry, URL string) (*Snapshot, error) {
+	snapshot := NewSnapshot(ctx, c, r, http.MethodGet, URL, nil)
+	if snapshot.ErrReq != nil {
+		return nil, snapshot.ErrReq
+	}
+	if snapshot.ErrRes != nil {
+		return nil, snapshot.ErrRes
+	}
+	if ok := (snapshot.StatusCode == http.StatusOK); ok != true {
+		return nil, errors.New(snapshot.Status)
+	}
+	return snapshot, nil
+}
+
+func newSnapshotRequest(ctx context.Context, HTTPMethod, URL string, HTTPCookies *[]*http.Cookie) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, HTTPMethod, URL, nil)
+	if err != nil {
+		return req, err
+	}
+	if HTTPCookies != nil {
 		for _, cookie := range *HTTPCookies {
 			req.AddCookie(cookie)
 		}
 	}
-	timeStart := time.Now()
-	res, errRes := c.Do(req)
-	timeEnd := time.Now()
-	return newSnapshot(HTTPMethod, URL, req, errReq, res, errRes, timeStart, timeEnd)
+	return req, err
 }
 
 func newSnapshot(HTTPMethod, URL string, req *http.Request, errReq error, res *http.Response, errRes error, timeStart, timeEnd time.Time) *Snapshot {
@@ -61,17 +131,23 @@ func newSnapshot(HTTPMethod, URL string, req *http.Request, errReq error, res *h
 	if (errReq != nil) && (errRes != nil) {
 		timeDuration = 0
 	}
+	var cache string
 	var status string
 	var statusCode int
 	if res != nil {
+		cache = res.Header.Get(SteamerCacheHeader)
+		res.Header.Del(SteamerCacheHeader)
 		status = res.Status
 		statusCode = res.StatusCode
 	}
-	doc, err := goquery.NewDocumentFromResponse(res)
+	body, doc, err := newSnapshotDocument(res)
 	return &Snapshot{
+		body:         body,
 		document:     doc,
 		request:      req,
 		response:     res,
+		BodyHash:     parseSnapshotBodyHash(body),
+		Cache:        cache,
 		ErrDoc:       err,
 		ErrReq:       errReq,
 		ErrRes:       errRes,
@@ -86,59 +162,110 @@ func newSnapshot(HTTPMethod, URL string, req *http.Request, errReq error, res *h
 		URL:          URL}
 }
 
-func hasVisitedURL(fullpath string, URL *url.URL) (bool, error) {
-	replacer := strings.NewReplacer("https://", "", "/", "", "\\", "", URL.Host, "", "=", "-", "?", ".")
-	filename := replacer.Replace(fmt.Sprintf("%s.json", URL.String()))
-	if strings.HasPrefix(filename, ".") {
-		filename = strings.TrimPrefix(filename, ".")
+func newSnapshotDocument(res *http.Response) ([]byte, *goquery.Document, error) {
+	if res == nil {
+		return nil, nil, errors.New("http.Response empty")
 	}
-	fullname := filepath.Join(fullpath, filename)
-	_, err := os.Stat(fullname)
-	if err == nil {
-		return true, nil
+	defer res.Body.Close()
+	// the body is kept so non-HTML responses (e.g. search/results JSON) can be decoded as well
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
 	}
-	if os.IsNotExist(err) {
-		return false, nil
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return body, nil, err
 	}
-	return true, err
+	doc.Url = res.Request.URL
+	return body, doc, nil
 }
 
-func hasVisitedURLDefault(URL *url.URL) (bool, error) {
-	user, err := user.Current()
+func (snapshot *Snapshot) Body() []byte {
+	return snapshot.body
+}
+
+func (snapshot *Snapshot) Document() *goquery.Document {
+	return snapshot.document
+}
+
+func visitedURL(fullpath string, URL *url.URL) (time.Time, bool, error) {
+	fullname := filepath.Join(fullpath, snapshotFilename(URL))
+	b, err := ioutil.ReadFile(fullname)
+	if os.IsNotExist(err) {
+		return time.Time{}, false, nil
+	}
 	if err != nil {
-		return false, err
+		return time.Time{}, true, err
 	}
-	fullpath := filepath.Join(user.HomeDir, "Desktop", "steambot", URL.Host)
-	return hasVisitedURL(fullpath, URL)
+	var steamerVisit SteamerVisit
+	err = json.Unmarshal(b, &steamerVisit)
+	return steamerVisit.TimeEnd, steamerVisit.OK(), err
 }
 
-func writeSnapshot(fullpath string, s *Snapshot) error {
-	err := os.MkdirAll(fullpath, os.ModePerm)
-	if err != nil {
-		return err
+func readSnapshotVisits(fullpath string, steamerVisits map[string]SteamerVisit) error {
+	files, err := ioutil.ReadDir(fullpath)
+	if os.IsNotExist(err) {
+		return nil
 	}
 	if err != nil {
 		return err
 	}
-	b, err := json.Marshal(s)
-	if err != nil {
-		return err
+	for _, file := range files {
+		if file.IsDir() || strings.HasSuffix(file.Name(), ".json") != true {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(fullpath, file.Name()))
+		if err != nil {
+			return err
+		}
+		var snapshot struct {
+			StatusCode int       `json:"status_code"`
+			TimeEnd    time.Time `json:"time_end"`
+			URL        string    `json:"URL"`
+		}
+		if err := json.Unmarshal(b, &snapshot); err != nil {
+			continue
+		}
+		URL, err := ParseNormalizedURL(snapshot.URL)
+		if err != nil || len(snapshot.URL) == 0 {
+			continue
+		}
+		steamerVisit := SteamerVisit{
+			StatusCode: snapshot.StatusCode,
+			TimeEnd:    snapshot.TimeEnd}
+		if steamerVisit.OK() != true {
+			continue
+		}
+		if x, ok := steamerVisits[URL]; ok && x.TimeEnd.After(snapshot.TimeEnd) {
+			continue
+		}
+		steamerVisits[URL] = steamerVisit
 	}
-	replacer := strings.NewReplacer("https://", "", "/", "", "\\", "", s.request.URL.Host, "", "=", "-", "?", ".")
-	filename := replacer.Replace(fmt.Sprintf("%s.json", s.request.URL.String()))
+	return nil
+}
+
+func snapshotFilename(URL *url.URL) string {
+	if appID := parseSteamAppID(URL.Path); appID > -1 {
+		return fmt.Sprintf("app-%d.json", appID)
+	}
+	replacer := strings.NewReplacer("https://", "", "/", "", "\\", "", URL.Host, "", "=", "-", "?", ".")
+	filename := replacer.Replace(fmt.Sprintf("%s.json", URL.String()))
 	if strings.HasPrefix(filename, ".") {
 		filename = strings.TrimPrefix(filename, ".")
 	}
-	fullname := filepath.Join(fullpath, filename)
-	err = ioutil.WriteFile(fullname, b, os.ModePerm)
-	return err
+	return filename
 }
 
-func writeSnapshotDefault(s *Snapshot) error {
-	user, err := user.Current()
+func writeSnapshot(fullpath string, s *Snapshot) error {
+	err := os.MkdirAll(fullpath, os.ModePerm)
 	if err != nil {
 		return err
 	}
-	fullpath := filepath.Join(user.HomeDir, "Desktop", "steambot", s.request.URL.Host)
-	return writeSnapshot(fullpath, s)
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	fullname := filepath.Join(fullpath, snapshotFilename(s.request.URL))
+	err = ioutil.WriteFile(fullname, b, os.ModePerm)
+	return err
 }
@@ -0,0 +1,33 @@
+package steamer
+
+import (
+	"net/http"
+	"time"
+)
+
+type SnapshotAttempt struct {
+	Err          string        `json:"err"`
+	RetryAfter   time.Duration `json:"retry_after"`
+	Status       string        `json:"status"`
+	StatusCode   int           `json:"status_code"`
+	TimeDuration time.Duration `json:"time_duration"`
+	TimeEnd      time.Time     `json:"time_end"`
+	TimeStart    time.Time     `json:"time_start"`
+	Wait         time.Duration `json:"wait"`
+}
+
+func NewSnapshotAttempt(res *http.Response, errRes error, timeStart, timeEnd time.Time) SnapshotAttempt {
+	snapshotAttempt := SnapshotAttempt{
+		TimeDuration: timeEnd.Sub(timeStart),
+		TimeEnd:      timeEnd,
+		TimeStart:    timeStart}
+	if errRes != nil {
+		snapshotAttempt.Err = errRes.Error()
+	}
+	if res != nil {
+		snapshotAttempt.RetryAfter = parseSnapshotRetryAfter(res.Header.Get("Retry-After"), timeEnd)
+		snapshotAttempt.Status = res.Status
+		snapshotAttempt.StatusCode = res.StatusCode
+	}
+	return snapshotAttempt
+}
@@ -0,0 +1,88 @@
+package steamer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+func parseSnapshotBodyHash(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	h := sha256.Sum256(body)
+	return hex.EncodeToString(h[:])
+}
+
+func compressSnapshotBody(body []byte) ([]byte, error) {
+	var b bytes.Buffer
+	gzipWriter := gzip.NewWriter(&b)
+	if _, err := gzipWriter.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func decompressSnapshotBody(b []byte) ([]byte, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+	return ioutil.ReadAll(gzipReader)
+}
+
+func snapshotBodyFilename(hash string) string {
+	return filepath.Join(hash[:2], fmt.Sprintf("%s.gz", hash))
+}
+
+func readSnapshotBody(fullpath string, hash string) ([]byte, error) {
+	if len(hash) < 2 {
+		return nil, fmt.Errorf("Snapshot.BodyHash %q invalid", hash)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(fullpath, snapshotBodyFilename(hash)))
+	if err != nil {
+		return nil, err
+	}
+	return decompressSnapshotBody(b)
+}
+
+func writeSnapshotBody(fullpath string, s *Snapshot) error {
+	if len(s.BodyHash) == 0 {
+		return nil
+	}
+	fullname := filepath.Join(fullpath, snapshotBodyFilename(s.BodyHash))
+	// bodies are content-addressed, so an existing file already holds these bytes
+	if _, err := os.Stat(fullname); err == nil {
+		return nil
+	}
+	err := os.MkdirAll(filepath.Dir(fullname), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	b, err := compressSnapshotBody(s.body)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(fullname), "*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), fullname)
+}
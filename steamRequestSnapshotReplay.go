@@ -0,0 +1,103 @@
+package steamer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type SnapshotReplay struct {
+	BodyHash   string    `json:"body_hash"`
+	StatusCode int       `json:"status_code"`
+	TimeEnd    time.Time `json:"time_end"`
+	URL        string    `json:"URL"`
+}
+
+func readSnapshotReplays(fullpath string) ([]SnapshotReplay, error) {
+	snapshotReplays := []SnapshotReplay{}
+	files, err := ioutil.ReadDir(fullpath)
+	if os.IsNotExist(err) {
+		return snapshotReplays, nil
+	}
+	if err != nil {
+		return snapshotReplays, err
+	}
+	for _, file := range files {
+		if file.IsDir() || strings.HasSuffix(file.Name(), ".json") != true {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(fullpath, file.Name()))
+		if err != nil {
+			return snapshotReplays, err
+		}
+		var snapshotReplay SnapshotReplay
+		// snapshots written before bodies were archived have no hash and cannot be replayed
+		if err := json.Unmarshal(b, &snapshotReplay); err != nil || len(snapshotReplay.BodyHash) == 0 {
+			continue
+		}
+		snapshotReplays = append(snapshotReplays, snapshotReplay)
+	}
+	return snapshotReplays, nil
+}
+
+func readSnapshotReplay(fullpath string, URL *url.URL) (SnapshotReplay, bool, error) {
+	var snapshotReplay SnapshotReplay
+	b, err := ioutil.ReadFile(filepath.Join(fullpath, snapshotFilename(URL)))
+	if os.IsNotExist(err) {
+		return snapshotReplay, false, nil
+	}
+	if err != nil {
+		return snapshotReplay, false, err
+	}
+	if err := json.Unmarshal(b, &snapshotReplay); err != nil {
+		return snapshotReplay, false, err
+	}
+	ok := (len(snapshotReplay.BodyHash) > 0 && snapshotReplay.StatusCode >= 200 && snapshotReplay.StatusCode < 300)
+	return snapshotReplay, ok, nil
+}
+
+func NewStoredSnapshot(replayStore ReplayStore, URL string) (*Snapshot, error) {
+	u, err := url.Parse(URL)
+	if err != nil {
+		return nil, err
+	}
+	snapshotReplay, ok, err := replayStore.ReadSnapshotReplay(u)
+	if err != nil {
+		return nil, err
+	}
+	if ok != true {
+		return nil, errors.New("SnapshotReplay empty")
+	}
+	body, err := replayStore.ReadSnapshotBody(snapshotReplay.BodyHash)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	doc.Url = u
+	return &Snapshot{
+		body:       body,
+		document:   doc,
+		BodyHash:   snapshotReplay.BodyHash,
+		Cache:      SnapshotCacheStored,
+		Method:     http.MethodGet,
+		RequestOK:  true,
+		ResponseOK: true,
+		Status:     fmt.Sprintf("%d %s", snapshotReplay.StatusCode, http.StatusText(snapshotReplay.StatusCode)),
+		StatusCode: snapshotReplay.StatusCode,
+		TimeEnd:    snapshotReplay.TimeEnd,
+		TimeStart:  snapshotReplay.TimeEnd,
+		URL:        URL}, nil
+}
@@ -0,0 +1,80 @@
+package steamer
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SnapshotRetry struct {
+	Backoff     time.Duration `json:"backoff"`
+	BackoffMax  time.Duration `json:"backoff_max"`
+	Jitter      float64       `json:"jitter"`
+	MaxAttempts int           `json:"max_attempts"`
+}
+
+func NewSnapshotRetry(maxAttempts int, backoff, backoffMax time.Duration, jitter float64) *SnapshotRetry {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	if backoffMax < backoff {
+		backoffMax = backoff
+	}
+	jitter = math.Max(0, math.Min(1, jitter))
+	return &SnapshotRetry{
+		Backoff:     backoff,
+		BackoffMax:  backoffMax,
+		Jitter:      jitter,
+		MaxAttempts: maxAttempts}
+}
+
+func (snapshotRetry *SnapshotRetry) Retry(attempt int, res *http.Response, errRes error) bool {
+	if snapshotRetry == nil {
+		return false
+	}
+	if ok := attempt < snapshotRetry.MaxAttempts; ok != true {
+		return false
+	}
+	if errRes != nil {
+		return true
+	}
+	if res == nil {
+		return false
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+}
+
+func (snapshotRetry *SnapshotRetry) Wait(attempt int, retryAfter time.Duration) time.Duration {
+	backoff := float64(snapshotRetry.Backoff) * math.Pow(2, float64(attempt-1))
+	backoff = math.Min(backoff, float64(snapshotRetry.BackoffMax))
+	backoff = backoff - (backoff * snapshotRetry.Jitter * rand.Float64())
+	wait := time.Duration(backoff)
+	if retryAfter > wait {
+		wait = retryAfter
+	}
+	return wait
+}
+
+func parseSnapshotRetryAfter(retryAfter string, now time.Time) time.Duration {
+	retryAfter = strings.TrimSpace(retryAfter)
+	if len(retryAfter) == 0 {
+		return 0
+	}
+	if n, err := strconv.Atoi(retryAfter); err == nil {
+		if n < 0 {
+			return 0
+		}
+		return time.Duration(n) * time.Second
+	}
+	t, err := http.ParseTime(retryAfter)
+	if err != nil {
+		return 0
+	}
+	if ok := t.After(now); ok != true {
+		return 0
+	}
+	return t.Sub(now)
+}
@@ -0,0 +1,56 @@
+package steamer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotRetryWait(t *testing.T) {
+	tests := []struct {
+		retry      *SnapshotRetry
+		attempt    int
+		retryAfter time.Duration
+		min        time.Duration
+		max        time.Duration
+	}{
+		{NewSnapshotRetry(5, time.Second, time.Second*8, 0), 1, 0, time.Second, time.Second},
+		{NewSnapshotRetry(5, time.Second, time.Second*8, 0), 2, 0, time.Second * 2, time.Second * 2},
+		{NewSnapshotRetry(5, time.Second, time.Second*8, 0), 4, 0, time.Second * 8, time.Second * 8},
+		{NewSnapshotRetry(5, time.Second, time.Second*8, 0), 5, 0, time.Second * 8, time.Second * 8},
+		{NewSnapshotRetry(5, time.Second, time.Second*8, 0), 1, time.Second * 30, time.Second * 30, time.Second * 30},
+		{NewSnapshotRetry(5, time.Second, time.Second*8, 0), 3, time.Second, time.Second * 4, time.Second * 4},
+		{NewSnapshotRetry(5, time.Second*4, time.Second, 0), 3, 0, time.Second * 4, time.Second * 4},
+		{NewSnapshotRetry(5, time.Second*4, time.Second*8, 0.5), 1, 0, time.Second * 2, time.Second * 4},
+		{NewSnapshotRetry(5, time.Second*4, time.Second*8, 2), 1, 0, 0, time.Second * 4},
+	}
+	for i, test := range tests {
+		for j := 0; j < 100; j++ {
+			got := test.retry.Wait(test.attempt, test.retryAfter)
+			if got < test.min || got > test.max {
+				t.Errorf("%d: SnapshotRetry.Wait(%d, %v) = %v, want [%v, %v]", i, test.attempt, test.retryAfter, got, test.min, test.max)
+				break
+			}
+		}
+	}
+}
+
+func TestParseSnapshotRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		retryAfter string
+		want       time.Duration
+	}{
+		{"120", time.Second * 120},
+		{" 5 ", time.Second * 5},
+		{"-1", 0},
+		{"", 0},
+		{"soon", 0},
+		{"Sun, 18 Oct 2026 12:01:00 GMT", time.Minute},
+		{"Sun, 18 Oct 2026 11:59:00 GMT", 0},
+	}
+	for _, test := range tests {
+		if got := parseSnapshotRetryAfter(test.retryAfter, now); got != test.want {
+			t.Errorf("parseSnapshotRetryAfter(%q) = %v, want %v", test.retryAfter, got, test.want)
+		}
+	}
+}
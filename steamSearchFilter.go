@@ -0,0 +1,36 @@
+package steamer
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type SteamSearchFilter struct {
+	Group string `json:"group"`
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Param string `json:"param"`
+	Value string `json:"value"`
+}
+
+func NewSteamSearchFilter(s *goquery.Selection) SteamSearchFilter {
+	name := strings.TrimSpace(s.AttrOr("data-loc", ""))
+	return SteamSearchFilter{
+		Group: scrapeSteamSearchFilterGroup(s),
+		Key:   parseSteamSearchQueryMapKey(name),
+		Name:  name,
+		Param: s.AttrOr("data-param", ""),
+		Value: s.AttrOr("data-value", "")}
+}
+
+func scrapeSteamSearchFilterGroup(s *goquery.Selection) string {
+	x := s.Closest("div.search_collapse_block")
+	if x.Length() == 0 {
+		return s.AttrOr("data-param", "")
+	}
+	if group := strings.TrimSpace(x.Find("div.block_header").First().Text()); len(group) > 0 {
+		return group
+	}
+	return x.AttrOr("data-collapse-name", s.AttrOr("data-param", ""))
+}
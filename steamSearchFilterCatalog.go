@@ -0,0 +1,143 @@
+package steamer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type SteamSearchFilterCatalog struct {
+	Filters   []SteamSearchFilter `json:"filters"`
+	Timestamp time.Time           `json:"timestamp"`
+	Version   string              `json:"version"`
+}
+
+type SteamSearchFilterCatalogDiff struct {
+	Added   []SteamSearchFilter `json:"added"`
+	Changed []SteamSearchFilter `json:"changed"`
+	Removed []SteamSearchFilter `json:"removed"`
+}
+
+func NewSteamSearchFilterCatalog(s *goquery.Selection) *SteamSearchFilterCatalog {
+	steamSearchFilters := []SteamSearchFilter{}
+	s.Each(func(i int, s *goquery.Selection) {
+		steamSearchFilter := NewSteamSearchFilter(s)
+		if len(steamSearchFilter.Param) == 0 || len(steamSearchFilter.Key) == 0 {
+			return
+		}
+		steamSearchFilters = append(steamSearchFilters, steamSearchFilter)
+	})
+	sort.SliceStable(steamSearchFilters, func(i, j int) bool {
+		return steamSearchFilters[i].Key < steamSearchFilters[j].Key
+	})
+	return &SteamSearchFilterCatalog{
+		Filters:   steamSearchFilters,
+		Timestamp: time.Now(),
+		Version:   parseSteamSearchFilterCatalogVersion(steamSearchFilters)}
+}
+
+func GetSteamSearchFilterCatalog(ctx context.Context, c *http.Client, r *SnapshotRetry) (*SteamSearchFilterCatalog, error) {
+	snapshot, err := GetSnapshot(ctx, c, r, SteamSearchURL)
+	if err != nil {
+		return nil, err
+	}
+	if ok := (snapshot.Document() != nil); ok != true {
+		return nil, snapshot.ErrDoc
+	}
+	s := snapshot.Document().Find("div.tab_filter_control[data-param]")
+	if ok := (s.Length() > 0); ok != true {
+		return nil, errors.New("goquery.Selection empty")
+	}
+	return NewSteamSearchFilterCatalog(s), nil
+}
+
+func (steamSearchFilterCatalog *SteamSearchFilterCatalog) Diff(previous *SteamSearchFilterCatalog) *SteamSearchFilterCatalogDiff {
+	steamSearchFilterCatalogDiff := &SteamSearchFilterCatalogDiff{
+		Added:   []SteamSearchFilter{},
+		Changed: []SteamSearchFilter{},
+		Removed: []SteamSearchFilter{}}
+	before := map[string]SteamSearchFilter{}
+	if previous != nil {
+		for _, x := range previous.Filters {
+			before[x.Key] = x
+		}
+	}
+	after := map[string]bool{}
+	for _, x := range steamSearchFilterCatalog.Filters {
+		if after[x.Key] {
+			continue
+		}
+		after[x.Key] = true
+		y, ok := before[x.Key]
+		if ok != true {
+			steamSearchFilterCatalogDiff.Added = append(steamSearchFilterCatalogDiff.Added, x)
+		} else if x != y {
+			steamSearchFilterCatalogDiff.Changed = append(steamSearchFilterCatalogDiff.Changed, x)
+		}
+	}
+	if previous != nil {
+		for _, x := range previous.Filters {
+			if after[x.Key] != true {
+				after[x.Key] = true
+				steamSearchFilterCatalogDiff.Removed = append(steamSearchFilterCatalogDiff.Removed, x)
+			}
+		}
+	}
+	return steamSearchFilterCatalogDiff
+}
+
+func (steamSearchFilterCatalog *SteamSearchFilterCatalog) QueryMap() *SteamSearchQueryMap {
+	steamSearchQueryMap := &SteamSearchQueryMap{}
+	for _, x := range steamSearchFilterCatalog.Filters {
+		steamSearchQueryMap.Add(x.Key, x.Param, x.Value)
+	}
+	return steamSearchQueryMap
+}
+
+func parseSteamSearchFilterCatalogVersion(s []SteamSearchFilter) string {
+	b, _ := json.Marshal(s)
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])[:12]
+}
+
+func readSteamSearchFilterCatalog(fullpath string) (*SteamSearchFilterCatalog, error) {
+	b, err := ioutil.ReadFile(filepath.Join(fullpath, "filters.json"))
+	if err != nil {
+		return nil, err
+	}
+	steamSearchFilterCatalog := &SteamSearchFilterCatalog{}
+	err = json.Unmarshal(b, steamSearchFilterCatalog)
+	return steamSearchFilterCatalog, err
+}
+
+func writeSteamSearchFilterCatalog(fullpath string, s *SteamSearchFilterCatalog) error {
+	err := os.MkdirAll(filepath.Join(fullpath, "filters"), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	// every distinct catalog is kept next to the current copy so older runs stay reproducible
+	filename := fmt.Sprintf("%d-%s.json", s.Timestamp.UnixNano(), s.Version)
+	matches, _ := filepath.Glob(filepath.Join(fullpath, "filters", fmt.Sprintf("*-%s.json", s.Version)))
+	if len(matches) == 0 {
+		err = ioutil.WriteFile(filepath.Join(fullpath, "filters", filename), b, os.ModePerm)
+		if err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(filepath.Join(fullpath, "filters.json"), b, os.ModePerm)
+}
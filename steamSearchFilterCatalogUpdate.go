@@ -0,0 +1,46 @@
+package steamer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type SteamSearchFilterCatalogUpdate struct {
+	*SteamSearchFilterCatalog
+	Diff     *SteamSearchFilterCatalogDiff `json:"diff"`
+	Previous *SteamSearchFilterCatalog     `json:"-"`
+}
+
+func UpdateSteamSearchFilterCatalog(ctx context.Context, c *http.Client, r *SnapshotRetry, fileStore *FileStore, offline bool) (*SteamSearchFilterCatalogUpdate, error) {
+	previous, _ := fileStore.ReadSteamSearchFilterCatalog()
+	steamSearchFilterCatalogUpdate := &SteamSearchFilterCatalogUpdate{
+		Previous: previous}
+	if offline {
+		if previous == nil {
+			return steamSearchFilterCatalogUpdate, errors.New("SteamSearchFilterCatalog empty (fetch it without offline first)")
+		}
+		steamSearchFilterCatalogUpdate.SteamSearchFilterCatalog = previous
+		steamSearchFilterCatalogUpdate.Diff = previous.Diff(previous)
+		return steamSearchFilterCatalogUpdate, nil
+	}
+	steamSearchFilterCatalog, err := GetSteamSearchFilterCatalog(ctx, c, r)
+	if err != nil {
+		return steamSearchFilterCatalogUpdate, err
+	}
+	steamSearchFilterCatalogUpdate.SteamSearchFilterCatalog = steamSearchFilterCatalog
+	steamSearchFilterCatalogUpdate.Diff = steamSearchFilterCatalog.Diff(previous)
+	return steamSearchFilterCatalogUpdate, fileStore.WriteSteamSearchFilterCatalog(steamSearchFilterCatalog)
+}
+
+func LoadSteamSearchFilterCatalog(ctx context.Context, c *http.Client, r *SnapshotRetry, fileStore *FileStore, offline bool) (*SteamSearchFilterCatalog, error) {
+	steamSearchFilterCatalogUpdate, err := UpdateSteamSearchFilterCatalog(ctx, c, r, fileStore, offline)
+	if steamSearchFilterCatalogUpdate.SteamSearchFilterCatalog != nil {
+		return steamSearchFilterCatalogUpdate.SteamSearchFilterCatalog, nil
+	}
+	// unreachable store pages fall back to the catalog seen on the last successful fetch
+	if steamSearchFilterCatalogUpdate.Previous != nil {
+		return steamSearchFilterCatalogUpdate.Previous, nil
+	}
+	return nil, err
+}
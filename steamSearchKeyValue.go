@@ -1,4 +1,4 @@
-package main
+package steamer
 
 type SteamSearchKeyValue struct {
 	Key   string
@@ -0,0 +1,84 @@
+package steamer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const SteamSearchPageCount int = 25
+
+type SteamSearchPagination struct {
+	LastPage   int `json:"last_page"`
+	TotalCount int `json:"total_count"`
+}
+
+func NewSteamSearchPagination(s *goquery.Selection) SteamSearchPagination {
+	steamSearchPagination := SteamSearchPagination{
+		LastPage:   scrapeSteamSearchPaginationLastPage(s),
+		TotalCount: scrapeSteamSearchPaginationTotalCount(s)}
+	if steamSearchPagination.LastPage == 0 && steamSearchPagination.TotalCount > 0 {
+		steamSearchPagination.LastPage = (steamSearchPagination.TotalCount + SteamSearchPageCount - 1) / SteamSearchPageCount
+	}
+	return steamSearchPagination
+}
+
+func GetSteamSearchPagination(ctx context.Context, c *http.Client, r *SnapshotRetry, searchMode, pageQuery string, page, count int) (SteamSearchPagination, error) {
+	if searchMode == SteamerSearchInfinite {
+		steamSearchResults, err := GetSteamSearchResults(ctx, c, r, pageQuery, page, count)
+		if err != nil {
+			return SteamSearchPagination{}, err
+		}
+		return NewSteamSearchResultsPagination(steamSearchResults, count), nil
+	}
+	snapshot, err := GetSnapshot(ctx, c, r, NewSteamSearchURL(pageQuery, page))
+	if err != nil {
+		return SteamSearchPagination{}, err
+	}
+	if ok := (snapshot.Document() != nil); ok != true {
+		return SteamSearchPagination{}, snapshot.ErrDoc
+	}
+	return NewSteamSearchPagination(snapshot.Document().Selection), nil
+}
+
+func NewSteamSearchURL(pageQuery string, page int) string {
+	URL := fmt.Sprintf("%s?", SteamSearchURL)
+	if ok := len(pageQuery) > 0; ok {
+		URL = fmt.Sprintf("%s%s&", URL, pageQuery)
+	}
+	return fmt.Sprintf("%spage=%d", URL, page)
+}
+
+func NewSteamSearchResultsPagination(s *SteamSearchResults, count int) SteamSearchPagination {
+	return SteamSearchPagination{
+		LastPage:   s.Pages(count),
+		TotalCount: s.TotalCount}
+}
+
+func scrapeSteamSearchPaginationLastPage(s *goquery.Selection) int {
+	var lastPage int
+	s.Find("div.search_pagination_right a").Each(func(i int, s *goquery.Selection) {
+		n, err := strconv.Atoi(strings.TrimSpace(s.Text()))
+		if err == nil && n > lastPage {
+			lastPage = n
+		}
+	})
+	return lastPage
+}
+
+func scrapeSteamSearchPaginationTotalCount(s *goquery.Selection) int {
+	substring := regexp.MustCompile(`of\s+([\d,.]+)`).FindStringSubmatch(s.Find("div.search_pagination_left").Text())
+	if len(substring) != 2 {
+		return 0
+	}
+	n, err := strconv.Atoi(regexp.MustCompile(`[^0-9]`).ReplaceAllString(substring[1], ""))
+	if err != nil {
+		return 0
+	}
+	return n
+}
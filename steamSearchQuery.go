@@ -0,0 +1,149 @@
+package steamer
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	SteamSearchControllerFull    string = "full"
+	SteamSearchControllerPartial string = "partial"
+)
+
+var steamSearchControllerCategories = map[string]int{
+	SteamSearchControllerFull:    28,
+	SteamSearchControllerPartial: 18}
+
+var steamSearchSortBy = map[string]bool{
+	"":              true,
+	"_ASC":          true,
+	"Name_ASC":      true,
+	"Price_ASC":     true,
+	"Price_DESC":    true,
+	"Released_DESC": true,
+	"Reviews_DESC":  true}
+
+type SteamSearchQuery struct {
+	Categories         []int               `json:"categories" toml:"categories" yaml:"categories"`
+	ControllerSupport  string              `json:"controller_support" toml:"controller_support" yaml:"controller_support"` // {ControllerSupport: "full"}
+	ExcludeTags        []int               `json:"exclude_tags" toml:"exclude_tags" yaml:"exclude_tags"`
+	HideFreeToPlay     bool                `json:"hide_free_to_play" toml:"hide_free_to_play" yaml:"hide_free_to_play"`
+	MaxPrice           string              `json:"max_price" toml:"max_price" yaml:"max_price"` // {MaxPrice: "free"} or {MaxPrice: "10"}
+	OS                 []string            `json:"os" toml:"os" yaml:"os"`                      // {OS: ["win", "linux"]}
+	Params             map[string][]string `json:"params" toml:"params" yaml:"params"`
+	SortBy             string              `json:"sort_by" toml:"sort_by" yaml:"sort_by"` // {SortBy: "Released_DESC"}
+	Specials           bool                `json:"specials" toml:"specials" yaml:"specials"`
+	SupportedLanguages []string            `json:"supported_languages" toml:"supported_languages" yaml:"supported_languages"`
+	Tags               []int               `json:"tags" toml:"tags" yaml:"tags"`
+	Term               string              `json:"term" toml:"term" yaml:"term"`
+}
+
+func (steamSearchQuery *SteamSearchQuery) Encode() string {
+	return steamSearchQuery.Values().Encode()
+}
+
+func (steamSearchQuery *SteamSearchQuery) Merge(rawQuery string) error {
+	values, err := url.ParseQuery(strings.TrimPrefix(rawQuery, "?"))
+	if err != nil {
+		return err
+	}
+	if steamSearchQuery.Params == nil {
+		steamSearchQuery.Params = map[string][]string{}
+	}
+	for key, value := range values {
+		steamSearchQuery.Params[key] = append(steamSearchQuery.Params[key], value...)
+	}
+	return nil
+}
+
+func (steamSearchQuery *SteamSearchQuery) Validate() error {
+	if steamSearchQuery.SortBy != "" && steamSearchSortBy[steamSearchQuery.SortBy] != true {
+		return fmt.Errorf("SteamSearchQuery.SortBy %q unknown", steamSearchQuery.SortBy)
+	}
+	if _, ok := steamSearchControllerCategories[steamSearchQuery.ControllerSupport]; steamSearchQuery.ControllerSupport != "" && ok != true {
+		return fmt.Errorf("SteamSearchQuery.ControllerSupport %q unknown", steamSearchQuery.ControllerSupport)
+	}
+	if steamSearchQuery.MaxPrice != "" && steamSearchQuery.MaxPrice != "free" {
+		if _, err := strconv.Atoi(steamSearchQuery.MaxPrice); err != nil {
+			return fmt.Errorf("SteamSearchQuery.MaxPrice %q not \"free\" or a whole number", steamSearchQuery.MaxPrice)
+		}
+	}
+	for _, OS := range steamSearchQuery.OS {
+		switch OS {
+		case "linux", "mac", "win":
+		default:
+			return fmt.Errorf("SteamSearchQuery.OS %q unknown", OS)
+		}
+	}
+	return nil
+}
+
+func (steamSearchQuery *SteamSearchQuery) Values() url.Values {
+	lists := map[string][]string{}
+	add := func(key string, values ...string) {
+		for _, value := range values {
+			for _, x := range strings.Split(value, ",") {
+				if x = strings.TrimSpace(x); len(x) > 0 {
+					lists[key] = append(lists[key], x)
+				}
+			}
+		}
+	}
+	values := url.Values{}
+	for key, value := range steamSearchQuery.Params {
+		switch key {
+		case "category1", "category2", "os", "supportedlang", "tags", "untags":
+			add(key, value...)
+		default:
+			values[key] = append([]string{}, value...)
+		}
+	}
+	for _, ID := range steamSearchQuery.Categories {
+		add("category1", strconv.Itoa(ID))
+	}
+	if ID, ok := steamSearchControllerCategories[steamSearchQuery.ControllerSupport]; ok {
+		add("category2", strconv.Itoa(ID))
+	}
+	for _, ID := range steamSearchQuery.ExcludeTags {
+		add("untags", strconv.Itoa(ID))
+	}
+	add("os", steamSearchQuery.OS...)
+	add("supportedlang", steamSearchQuery.SupportedLanguages...)
+	for _, ID := range steamSearchQuery.Tags {
+		add("tags", strconv.Itoa(ID))
+	}
+	for key, list := range lists {
+		seen := map[string]bool{}
+		unique := []string{}
+		for _, x := range list {
+			if seen[x] != true {
+				seen[x] = true
+				unique = append(unique, x)
+			}
+		}
+		sort.Strings(unique)
+		values.Set(key, strings.Join(unique, ","))
+	}
+	if steamSearchQuery.HideFreeToPlay {
+		values.Set("hidef2p", "1")
+	}
+	if len(steamSearchQuery.MaxPrice) > 0 {
+		values.Set("maxprice", steamSearchQuery.MaxPrice)
+	}
+	if len(steamSearchQuery.SortBy) > 0 {
+		values.Set("sort_by", steamSearchQuery.SortBy)
+	}
+	if steamSearchQuery.Specials {
+		values.Set("specials", "1")
+	}
+	if len(steamSearchQuery.Term) > 0 {
+		values.Set("term", strings.TrimSpace(strings.Join(append(values["term"], steamSearchQuery.Term), " ")))
+	}
+	for _, value := range values {
+		sort.Strings(value)
+	}
+	return values
+}
@@ -1,7 +1,10 @@
-package main
+package steamer
 
 import (
+	"fmt"
+	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -10,8 +13,6 @@ import (
 type SteamSearchQueryMap map[string]*SteamSearchKeyValue
 
 func NewSteamSearchQueryMap(s *goquery.Selection) *SteamSearchQueryMap {
-	replacer := strings.NewReplacer("/", "-", "\\", "-", " ", "-", "&", "and", "+", "-")
-	regexp := regexp.MustCompile(`-{2,}`)
 	steamSearchQueryMap := &SteamSearchQueryMap{}
 	s.Each(func(i int, s *goquery.Selection) {
 		dataParam, ok := s.Attr("data-param")
@@ -26,10 +27,7 @@ func NewSteamSearchQueryMap(s *goquery.Selection) *SteamSearchQueryMap {
 		if ok != true {
 			return
 		}
-		dataLoc = replacer.Replace(dataLoc)
-		dataLoc = strings.ToUpper(dataLoc)
-		dataLoc = regexp.ReplaceAllString(dataLoc, "")
-		steamSearchQueryMap.Add(dataLoc, dataParam, dataValue)
+		steamSearchQueryMap.Add(parseSteamSearchQueryMapKey(dataLoc), dataParam, dataValue)
 	})
 	return steamSearchQueryMap
 }
@@ -51,3 +49,31 @@ func (steamSearchQueryMap *SteamSearchQueryMap) Has(dataLoc string) bool {
 	_, ok := steamSearchQueryMap.Get(dataLoc)
 	return ok
 }
+
+func (steamSearchQueryMap *SteamSearchQueryMap) Query(dataLocs []string) (string, error) {
+	querySet := map[string][]string{}
+	for _, dataLoc := range dataLocs {
+		steamSearchKeyValue, ok := steamSearchQueryMap.Get(strings.ToUpper(strings.TrimSpace(dataLoc)))
+		if ok != true {
+			return "", fmt.Errorf("SteamSearchQueryMap %q unknown", dataLoc)
+		}
+		querySet[steamSearchKeyValue.Key] = append(querySet[steamSearchKeyValue.Key], steamSearchKeyValue.Value)
+	}
+	var keys []string
+	for key := range querySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	queryQueue := []string{}
+	for _, key := range keys {
+		queryQueue = append(queryQueue, fmt.Sprintf("%s=%s", url.QueryEscape(key), url.QueryEscape(strings.Join(querySet[key], ","))))
+	}
+	return strings.Join(queryQueue, "&"), nil
+}
+
+func parseSteamSearchQueryMapKey(dataLoc string) string {
+	replacer := strings.NewReplacer("/", "-", "\\", "-", " ", "-", "&", "and", "+", "-")
+	dataLoc = replacer.Replace(dataLoc)
+	dataLoc = strings.ToUpper(dataLoc)
+	return regexp.MustCompile(`-{2,}`).ReplaceAllString(dataLoc, "")
+}
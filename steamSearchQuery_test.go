@@ -0,0 +1,63 @@
+package steamer
+
+import "testing"
+
+func TestSteamSearchQueryEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    *SteamSearchQuery
+		rawQuery string
+		want     string
+	}{
+		{"empty", &SteamSearchQuery{}, "", ""},
+		{"fields", &SteamSearchQuery{
+			Categories:         []int{2, 1},
+			ControllerSupport:  SteamSearchControllerFull,
+			ExcludeTags:        []int{9},
+			HideFreeToPlay:     true,
+			MaxPrice:           "free",
+			OS:                 []string{"win", "linux"},
+			SortBy:             "Released_DESC",
+			Specials:           true,
+			SupportedLanguages: []string{"english"},
+			Tags:               []int{19, 492},
+			Term:               "half life"},
+			"",
+			"category1=1%2C2&category2=28&hidef2p=1&maxprice=free&os=linux%2Cwin&sort_by=Released_DESC&specials=1&supportedlang=english&tags=19%2C492&term=half+life&untags=9"},
+		// list params from the raw query are merged with the fields and deduplicated
+		{"merge", &SteamSearchQuery{
+			OS:   []string{"win"},
+			Tags: []int{19},
+			Term: "life"},
+			"?os=mac,win&tags=19&term=half",
+			"os=mac%2Cwin&tags=19&term=half+life"},
+		{"params", &SteamSearchQuery{}, "ignore_preferences=1&ndl=1", "ignore_preferences=1&ndl=1"},
+	}
+	for _, test := range tests {
+		if err := test.query.Merge(test.rawQuery); err != nil {
+			t.Fatalf("%s: SteamSearchQuery.Merge(%q): %v", test.name, test.rawQuery, err)
+		}
+		if got := test.query.Encode(); got != test.want {
+			t.Errorf("%s: SteamSearchQuery.Encode() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSteamSearchQueryValidate(t *testing.T) {
+	tests := []struct {
+		query *SteamSearchQuery
+		ok    bool
+	}{
+		{&SteamSearchQuery{}, true},
+		{&SteamSearchQuery{ControllerSupport: SteamSearchControllerPartial, MaxPrice: "10", OS: []string{"mac"}, SortBy: "Name_ASC"}, true},
+		{&SteamSearchQuery{ControllerSupport: "some"}, false},
+		{&SteamSearchQuery{MaxPrice: "cheap"}, false},
+		{&SteamSearchQuery{OS: []string{"dos"}}, false},
+		{&SteamSearchQuery{SortBy: "Newest"}, false},
+	}
+	for _, test := range tests {
+		if err := test.query.Validate(); (err == nil) != test.ok {
+			t.Errorf("SteamSearchQuery.Validate(%+v) = %v, want ok %v", test.query, err, test.ok)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package steamer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const SteamSearchResultsURL string = "https://store.steampowered.com/search/results/"
+
+const SteamSearchResultsCount int = 50
+
+type SteamSearchResults struct {
+	ResultsHTML string `json:"results_html"`
+	Start       int    `json:"start"`
+	Success     int    `json:"success"`
+	TotalCount  int    `json:"total_count"`
+}
+
+func NewSteamSearchResults(b []byte) (*SteamSearchResults, error) {
+	steamSearchResults := &SteamSearchResults{}
+	err := json.Unmarshal(b, steamSearchResults)
+	if err != nil {
+		return nil, err
+	}
+	if steamSearchResults.Success != 1 {
+		return steamSearchResults, fmt.Errorf("SteamSearchResults.Success %d", steamSearchResults.Success)
+	}
+	return steamSearchResults, nil
+}
+
+func GetSteamSearchResults(ctx context.Context, c *http.Client, r *SnapshotRetry, pageQuery string, page, count int) (*SteamSearchResults, error) {
+	snapshot, err := GetSnapshot(ctx, c, r, NewSteamSearchResultsURL(pageQuery, page, count))
+	if err != nil {
+		return nil, err
+	}
+	return NewSteamSearchResults(snapshot.Body())
+}
+
+func NewSteamSearchResultsURL(pageQuery string, page, count int) string {
+	if count <= 0 {
+		count = SteamSearchResultsCount
+	}
+	URL := fmt.Sprintf("%s?", SteamSearchResultsURL)
+	if ok := len(pageQuery) > 0; ok {
+		URL = fmt.Sprintf("%s%s&", URL, pageQuery)
+	}
+	return fmt.Sprintf("%scount=%d&infinite=1&start=%d", URL, count, (page-1)*count)
+}
+
+func (steamSearchResults *SteamSearchResults) Pages(count int) int {
+	if count <= 0 {
+		count = SteamSearchResultsCount
+	}
+	return (steamSearchResults.TotalCount + count - 1) / count
+}
+
+func (steamSearchResults *SteamSearchResults) Selection() (*goquery.Selection, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(steamSearchResults.ResultsHTML))
+	if err != nil {
+		return nil, err
+	}
+	return doc.Find("a.search_result_row[href]"), nil
+}
+
+func (crawler *Crawler) onGetSteamSearchResults(ctx context.Context, URL string, revisit bool, snap func(s *Snapshot), results func(s *SteamSearchResults), success func(s *SteamGameAbbreviation), skip func(s *SteamGameAbbreviation), err func(e error)) {
+	snapshot := crawler.snapshot(ctx, URL, revisit, nil)
+	if ok := (ctx.Err() != nil && snapshot.StatusCode == 0); ok {
+		err(ctx.Err())
+		return
+	}
+	snap(snapshot)
+	if ok := (snapshot.StatusCode == http.StatusOK); ok != true {
+		err(errors.New(snapshot.Status))
+		return
+	}
+	steamSearchResults, e := NewSteamSearchResults(snapshot.Body())
+	if e != nil {
+		err(e)
+		return
+	}
+	results(steamSearchResults)
+	goQuerySelection, e := steamSearchResults.Selection()
+	if e != nil {
+		err(e)
+		return
+	}
+	// an empty page past total_count is a valid answer
+	eachSteamGameAbbreviation(goQuerySelection, success, skip)
+}
@@ -0,0 +1,20 @@
+package steamer
+
+import "testing"
+
+func TestNewSteamSearchResultsURL(t *testing.T) {
+	tests := []struct {
+		pageQuery string
+		page      int
+		count     int
+		want      string
+	}{
+		{"", 1, 0, SteamSearchResultsURL + "?count=50&infinite=1&start=0"},
+		{"tags=19", 3, 25, SteamSearchResultsURL + "?tags=19&count=25&infinite=1&start=50"},
+	}
+	for _, test := range tests {
+		if got := NewSteamSearchResultsURL(test.pageQuery, test.page, test.count); got != test.want {
+			t.Errorf("NewSteamSearchResultsURL(%q, %d, %d) = %q, want %q", test.pageQuery, test.page, test.count, got, test.want)
+		}
+	}
+}
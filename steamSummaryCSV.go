@@ -1,11 +1,10 @@
-package main
+package steamer
 
 import (
 	"encoding/csv"
 	"errors"
 	"fmt"
 	"os"
-	"os/user"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -68,13 +67,3 @@ func writeSteamSummaryCSV(fullpath string, name string, s *[]SteamSummaryCSV) er
 	}
 	return nil
 }
-
-func writeSteamSummaryCSVDefault(name string, s *[]SteamSummaryCSV) error {
-	user, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-	fullpath := filepath.Join(user.HomeDir, "Desktop", "steambot")
-	err = writeSteamSummaryCSV(fullpath, name, s)
-	return err
-}
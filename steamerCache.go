@@ -0,0 +1,257 @@
+package steamer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const SteamerCacheHeader string = "X-Steamer-Cache"
+
+const (
+	SnapshotCacheFetch       string = "fetch"
+	SnapshotCacheHit         string = "hit"
+	SnapshotCacheNotModified string = "304"
+	SnapshotCacheStored      string = "stored"
+)
+
+type SteamerCache struct {
+	Fullpath  string
+	Output    io.Writer
+	Transport http.RoundTripper
+}
+
+type steamerCacheEntry struct {
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag"`
+	Expires      time.Time   `json:"expires"`
+	Header       http.Header `json:"header"`
+	LastModified string      `json:"last_modified"`
+	Status       string      `json:"status"`
+	StatusCode   int         `json:"status_code"`
+	TimeStored   time.Time   `json:"time_stored"`
+	URL          string      `json:"URL"`
+}
+
+func NewSteamerCache(fullpath string, transport http.RoundTripper) (*SteamerCache, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	err := os.MkdirAll(fullpath, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	return &SteamerCache{
+		Fullpath:  fullpath,
+		Output:    os.Stdout,
+		Transport: transport}, nil
+}
+
+func (steamerCache *SteamerCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return steamerCache.Transport.RoundTrip(req)
+	}
+	fullname := steamerCache.filename(req)
+	entry, err := readSteamerCacheEntry(fullname)
+	if err != nil {
+		entry = nil
+	}
+	now := time.Now()
+	if entry != nil && now.Before(entry.Expires) {
+		return entry.response(req, SnapshotCacheHit)
+	}
+	if entry != nil {
+		// RoundTrip must not modify the caller's request
+		req = req.Clone(req.Context())
+		if len(entry.ETag) > 0 {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if len(entry.LastModified) > 0 {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+	res, err := steamerCache.Transport.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+	if entry != nil && res.StatusCode == http.StatusNotModified {
+		res.Body.Close()
+		entry.Expires = parseSteamerCacheExpires(res.Header, now)
+		entry.TimeStored = now
+		if x := res.Header.Get("ETag"); len(x) > 0 {
+			entry.ETag = x
+		}
+		if x := res.Header.Get("Last-Modified"); len(x) > 0 {
+			entry.LastModified = x
+		}
+		steamerCache.write(fullname, entry)
+		return entry.response(req, SnapshotCacheNotModified)
+	}
+	res.Header.Set(SteamerCacheHeader, SnapshotCacheFetch)
+	if ok := (res.StatusCode == http.StatusOK && isSteamerCacheStorable(res.Header)); ok != true {
+		return res, nil
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	header := res.Header.Clone()
+	header.Del(SteamerCacheHeader)
+	header.Del("Set-Cookie")
+	steamerCache.write(fullname, &steamerCacheEntry{
+		Body:         body,
+		ETag:         res.Header.Get("ETag"),
+		Expires:      parseSteamerCacheExpires(res.Header, now),
+		Header:       header,
+		LastModified: res.Header.Get("Last-Modified"),
+		Status:       res.Status,
+		StatusCode:   res.StatusCode,
+		TimeStored:   now,
+		URL:          req.URL.String()})
+	return res, nil
+}
+
+func (steamerCache *SteamerCache) write(fullname string, entry *steamerCacheEntry) {
+	// the response is still served, the next request for it is just fetched again
+	if err := writeSteamerCacheEntry(fullname, entry); err != nil {
+		fmt.Fprintln(steamerCache.Output, "cache", "\t", "->", entry.URL, err)
+	}
+}
+
+func (steamerCache *SteamerCache) filename(req *http.Request) string {
+	// cookies decide what Steam renders (e.g. the age gate), so they are part of the key
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s", req.URL.String(), req.Header.Get("Cookie"))))
+	hash := hex.EncodeToString(h[:])
+	return filepath.Join(steamerCache.Fullpath, hash[:2], fmt.Sprintf("%s.json.gz", hash))
+}
+
+func (steamerCacheEntry *steamerCacheEntry) response(req *http.Request, cache string) (*http.Response, error) {
+	header := steamerCacheEntry.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(SteamerCacheHeader, cache)
+	header.Set("Content-Length", strconv.Itoa(len(steamerCacheEntry.Body)))
+	return &http.Response{
+		Body:          ioutil.NopCloser(bytes.NewReader(steamerCacheEntry.Body)),
+		ContentLength: int64(len(steamerCacheEntry.Body)),
+		Header:        header,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Request:       req,
+		Status:        steamerCacheEntry.Status,
+		StatusCode:    steamerCacheEntry.StatusCode}, nil
+}
+
+func isSteamerCacheStorable(header http.Header) bool {
+	cacheControl := parseSteamerCacheControl(header)
+	if _, ok := cacheControl["no-store"]; ok {
+		return false
+	}
+	_, ok := cacheControl["max-age"]
+	return ok || len(header.Get("ETag")) > 0 || len(header.Get("Last-Modified")) > 0
+}
+
+func parseSteamerCacheControl(header http.Header) map[string]string {
+	cacheControl := map[string]string{}
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			directive = strings.TrimSpace(directive)
+			if len(directive) == 0 {
+				continue
+			}
+			key, value := directive, ""
+			if i := strings.Index(directive, "="); i > -1 {
+				key, value = directive[:i], strings.Trim(directive[i+1:], "\"")
+			}
+			cacheControl[strings.ToLower(key)] = value
+		}
+	}
+	return cacheControl
+}
+
+func parseSteamerCacheExpires(header http.Header, now time.Time) time.Time {
+	cacheControl := parseSteamerCacheControl(header)
+	if _, ok := cacheControl["no-cache"]; ok {
+		return now
+	}
+	if value, ok := cacheControl["max-age"]; ok {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return now
+		}
+		seconds = seconds - parseSteamerCacheAge(header)
+		if seconds < 0 {
+			return now
+		}
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	// without max-age every reuse is revalidated first
+	return now
+}
+
+func parseSteamerCacheAge(header http.Header) int {
+	age, err := strconv.Atoi(header.Get("Age"))
+	if err != nil || age < 0 {
+		return 0
+	}
+	return age
+}
+
+func readSteamerCacheEntry(fullname string) (*steamerCacheEntry, error) {
+	b, err := ioutil.ReadFile(fullname)
+	if err != nil {
+		return nil, err
+	}
+	b, err = decompressSnapshotBody(b)
+	if err != nil {
+		return nil, err
+	}
+	entry := &steamerCacheEntry{}
+	err = json.Unmarshal(b, entry)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func writeSteamerCacheEntry(fullname string, entry *steamerCacheEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	b, err = compressSnapshotBody(b)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(fullname), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(fullname), "*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), fullname)
+}
@@ -0,0 +1,89 @@
+package steamer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseSteamerCacheExpires(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header http.Header
+		want   time.Duration
+	}{
+		{http.Header{"Cache-Control": {"max-age=60"}}, time.Minute},
+		{http.Header{"Cache-Control": {"public, max-age=\"60\""}}, time.Minute},
+		{http.Header{"Cache-Control": {"max-age=60"}, "Age": {"20"}}, time.Second * 40},
+		{http.Header{"Cache-Control": {"max-age=60"}, "Age": {"120"}}, 0},
+		{http.Header{"Cache-Control": {"max-age=60, no-cache"}}, 0},
+		{http.Header{"Cache-Control": {"max-age=-1"}}, 0},
+		{http.Header{"ETag": {"\"a\""}}, 0},
+		{http.Header{}, 0},
+	}
+	for _, test := range tests {
+		if got := parseSteamerCacheExpires(test.header, now).Sub(now); got != test.want {
+			t.Errorf("parseSteamerCacheExpires(%v) = %v, want %v", test.header, got, test.want)
+		}
+	}
+}
+
+func TestSteamerCacheRoundTrip(t *testing.T) {
+	tests := []struct {
+		cacheControl string
+		etag         string
+		want         []string
+		fetches      int
+	}{
+		// fresh entries are served without a request
+		{"max-age=60", "", []string{SnapshotCacheFetch, SnapshotCacheHit, SnapshotCacheHit}, 1},
+		// expired entries are revalidated and the stored body is served on 304
+		{"max-age=0", "\"v1\"", []string{SnapshotCacheFetch, SnapshotCacheNotModified, SnapshotCacheNotModified}, 3},
+		{"", "\"v1\"", []string{SnapshotCacheFetch, SnapshotCacheNotModified, SnapshotCacheNotModified}, 3},
+		// responses that must not be stored are fetched every time
+		{"no-store", "\"v1\"", []string{SnapshotCacheFetch, SnapshotCacheFetch, SnapshotCacheFetch}, 3},
+		{"", "", []string{SnapshotCacheFetch, SnapshotCacheFetch, SnapshotCacheFetch}, 3},
+	}
+	for _, test := range tests {
+		fetches := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches++
+			if len(test.cacheControl) > 0 {
+				w.Header().Set("Cache-Control", test.cacheControl)
+			}
+			if len(test.etag) > 0 {
+				w.Header().Set("ETag", test.etag)
+				if r.Header.Get("If-None-Match") == test.etag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			}
+			w.Write([]byte("<html>body</html>"))
+		}))
+		steamerCache, err := NewSteamerCache(t.TempDir(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &http.Client{Transport: steamerCache}
+		for i, want := range test.want {
+			res, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			if got := res.Header.Get(SteamerCacheHeader); got != want {
+				t.Errorf("%q %q: request %d cache = %q, want %q", test.cacheControl, test.etag, i, got, want)
+			}
+			if res.StatusCode != http.StatusOK || string(b) != "<html>body</html>" {
+				t.Errorf("%q %q: request %d = %d %q", test.cacheControl, test.etag, i, res.StatusCode, b)
+			}
+		}
+		if fetches != test.fetches {
+			t.Errorf("%q %q: fetches = %d, want %d", test.cacheControl, test.etag, fetches, test.fetches)
+		}
+		server.Close()
+	}
+}
@@ -0,0 +1,101 @@
+package steamer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type SteamerConfig struct {
+	Cache           string            `json:"cache" toml:"cache" yaml:"cache"`
+	Depth           string            `json:"depth" toml:"depth" yaml:"depth"`
+	Farm            int               `json:"farm" toml:"farm" yaml:"farm"`
+	Filters         []string          `json:"filters" toml:"filters" yaml:"filters"`
+	Journal         string            `json:"journal" toml:"journal" yaml:"journal"`
+	LimitCharts     SteamerHostLimit  `json:"limit_charts" toml:"limit_charts" yaml:"limit_charts"`
+	LimitStore      SteamerHostLimit  `json:"limit_store" toml:"limit_store" yaml:"limit_store"`
+	Options         string            `json:"options" toml:"options" yaml:"options"`
+	Out             string            `json:"out" toml:"out" yaml:"out"`
+	PagesAll        bool              `json:"pages_all" toml:"pages_all" yaml:"pages_all"`
+	PagesFrom       int               `json:"pages_from" toml:"pages_from" yaml:"pages_from"`
+	PagesTo         int               `json:"pages_to" toml:"pages_to" yaml:"pages_to"`
+	Retry           int               `json:"retry" toml:"retry" yaml:"retry"`
+	RetryBackoff    string            `json:"retry_backoff" toml:"retry_backoff" yaml:"retry_backoff"`
+	RetryBackoffMax string            `json:"retry_backoff_max" toml:"retry_backoff_max" yaml:"retry_backoff_max"`
+	RetryJitter     float64           `json:"retry_jitter" toml:"retry_jitter" yaml:"retry_jitter"`
+	Revisit         *SteamerRevisit   `json:"revisit" toml:"revisit" yaml:"revisit"`
+	Search          *SteamSearchQuery `json:"search" toml:"search" yaml:"search"`
+	SearchCount     int               `json:"search_count" toml:"search_count" yaml:"search_count"`
+	SearchMode      string            `json:"search_mode" toml:"search_mode" yaml:"search_mode"`
+	SQLite          string            `json:"sqlite" toml:"sqlite" yaml:"sqlite"`
+	Store           string            `json:"store" toml:"store" yaml:"store"`
+	TerminateZero   bool              `json:"terminate_zero" toml:"terminate_zero" yaml:"terminate_zero"`
+	Verbose         bool              `json:"verbose" toml:"verbose" yaml:"verbose"`
+	WARC            string            `json:"warc" toml:"warc" yaml:"warc"`
+	WARCReplay      string            `json:"warc_replay" toml:"warc_replay" yaml:"warc_replay"`
+	WARCSize        int               `json:"warc_size" toml:"warc_size" yaml:"warc_size"`
+	Write           *SteamerWrite     `json:"write" toml:"write" yaml:"write"`
+
+	keys map[string]bool
+}
+
+func (steamerConfig *SteamerConfig) Read(name string) error {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	steamerConfig.keys = map[string]bool{}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(steamerConfig)
+		if err == nil {
+			var m map[string]interface{}
+			err = json.Unmarshal(b, &m)
+			steamerConfig.addKeys("", m)
+		}
+	case ".toml":
+		var metaData toml.MetaData
+		metaData, err = toml.Decode(string(b), steamerConfig)
+		if err == nil && len(metaData.Undecoded()) > 0 {
+			err = fmt.Errorf("SteamerConfig %s unknown key %q", name, metaData.Undecoded()[0].String())
+		}
+		for _, key := range metaData.Keys() {
+			steamerConfig.keys[strings.ToLower(key.String())] = true
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(b))
+		decoder.KnownFields(true)
+		err = decoder.Decode(steamerConfig)
+		if err == nil {
+			var m map[string]interface{}
+			err = yaml.Unmarshal(b, &m)
+			steamerConfig.addKeys("", m)
+		}
+	default:
+		err = fmt.Errorf("SteamerConfig %s unknown format (want .json, .toml, .yaml)", name)
+	}
+	return err
+}
+
+func (steamerConfig *SteamerConfig) addKeys(prefix string, m map[string]interface{}) {
+	for key, value := range m {
+		key = strings.ToLower(prefix + key)
+		steamerConfig.keys[key] = true
+		if v, ok := value.(map[string]interface{}); ok {
+			steamerConfig.addKeys(key+".", v)
+		}
+	}
+}
+
+func (steamerConfig *SteamerConfig) Has(key string) bool {
+	// set keys are applied even when they match the flag default, e.g. {revisit: {search: false}}
+	return steamerConfig.keys[strings.ToLower(key)]
+}
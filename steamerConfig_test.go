@@ -0,0 +1,64 @@
+package steamer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestSteamerConfigHas(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"profile.json", `{"pages_from": 0, "revisit": {"search": false}, "write": {"csv": false}}`},
+		{"profile.toml", "pages_from = 0\n[revisit]\nsearch = false\n[write]\ncsv = false\n"},
+		{"profile.yaml", "pages_from: 0\nrevisit:\n  search: false\nwrite:\n  csv: false\n"},
+	}
+	for _, test := range tests {
+		name := filepath.Join(t.TempDir(), test.name)
+		if err := ioutil.WriteFile(name, []byte(test.body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		steamerConfig := &SteamerConfig{}
+		if err := steamerConfig.Read(name); err != nil {
+			t.Fatalf("%s: SteamerConfig.Read: %v", test.name, err)
+		}
+		// keys set to their zero value are still present
+		for key, want := range map[string]bool{
+			"pages_from":     true,
+			"revisit":        true,
+			"revisit.search": true,
+			"Revisit.Search": true,
+			"write.csv":      true,
+			"pages_to":       false,
+			"revisit.game":   false,
+			"write.log":      false,
+		} {
+			if got := steamerConfig.Has(key); got != want {
+				t.Errorf("%s: SteamerConfig.Has(%q) = %t, want %t", test.name, key, got, want)
+			}
+		}
+	}
+}
+
+func TestSteamerConfigReadUnknown(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"profile.json", `{"pages": 1}`},
+		{"profile.toml", "pages = 1\n"},
+		{"profile.yaml", "pages: 1\n"},
+		{"profile.ini", "pages=1\n"},
+	}
+	for _, test := range tests {
+		name := filepath.Join(t.TempDir(), test.name)
+		if err := ioutil.WriteFile(name, []byte(test.body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := (&SteamerConfig{}).Read(name); err == nil {
+			t.Errorf("%s: SteamerConfig.Read = nil, want an error", test.name)
+		}
+	}
+}
@@ -0,0 +1,708 @@
+package steamer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const SteamSearchURL string = "https://store.steampowered.com/search/"
+
+const (
+	SteamerSearchInfinite string = "infinite"
+	SteamerSearchPage     string = "page"
+)
+
+const SteamerWorkers int = 8
+
+type Crawler struct {
+	Client     *http.Client
+	DeadLetter *SteamerDeadLetter
+	Journal    *SteamerJournal
+	Limiter    *SteamerLimiter
+	Log        *SteamerLog
+	Options    *CrawlerOptions
+	Output     io.Writer
+	Store      Store
+	Summary    *SteamerSummary
+	SummaryCSV []SteamSummaryCSV
+	WARC       *SteamerWARC
+
+	claimed   map[string]bool
+	cond      *sync.Cond
+	discovery string
+	mu        *sync.Mutex
+	queue     []SteamerTask
+	resume    []SteamerTask
+	resumed   map[string]bool
+	stopped   bool
+	wg        *sync.WaitGroup
+}
+
+type CrawlerOptions struct {
+	Depth         string
+	Limiter       *SteamerLimiter
+	Limits        map[string]SteamerHostLimit
+	PageQuery     string
+	PagesAll      bool
+	PagesFrom     int
+	PagesTo       int
+	Retry         *SnapshotRetry
+	Revisit       *SteamerRevisit
+	RunID         string
+	SearchMode    string
+	SearchCount   int
+	TerminateZero bool
+	Verbose       bool
+	Workers       int
+	Write         *SteamerWrite
+}
+
+func NewCrawler(c *http.Client, store Store, options *CrawlerOptions) *Crawler {
+	if options.PagesFrom <= 0 {
+		options.PagesFrom = 1
+	}
+	if options.PagesTo <= 0 {
+		options.PagesTo = 1
+	}
+	// -all discovers the last page, so a -to below -from is never meant as the start of the range
+	if options.PagesAll && options.PagesTo < options.PagesFrom {
+		options.PagesTo = options.PagesFrom
+	}
+	if ok := options.PagesFrom > options.PagesTo; ok {
+		options.PagesTo, options.PagesFrom = options.PagesFrom, options.PagesTo
+	}
+	if len(options.Depth) == 0 {
+		options.Depth = SteamerStageChart
+	}
+	if options.Revisit == nil {
+		options.Revisit = &SteamerRevisit{}
+	}
+	if options.Write == nil {
+		options.Write = NewSteamerWrite(-1)
+	}
+	if len(options.RunID) == 0 {
+		options.RunID = NewSteamerRunID()
+	}
+	if len(options.SearchMode) == 0 {
+		options.SearchMode = SteamerSearchPage
+	}
+	if options.SearchCount <= 0 {
+		options.SearchCount = SteamSearchResultsCount
+	}
+	if options.Workers <= 0 {
+		for _, limit := range options.Limits {
+			options.Workers = options.Workers + limit.Concurrency
+		}
+	}
+	if options.Workers <= 0 {
+		options.Workers = SteamerWorkers
+	}
+	client := *c
+	// a limiter handed in is already behind c, so requests made before the crawl share its slots
+	steamerLimiter := options.Limiter
+	if steamerLimiter == nil {
+		steamerLimiter = NewSteamerLimiter(c.Transport, options.Limits)
+		client.Transport = steamerLimiter
+	}
+	mu := &sync.Mutex{}
+	return &Crawler{
+		Client: &client,
+		DeadLetter: &SteamerDeadLetter{
+			Failures:  []SteamerFailure{},
+			PagesFrom: options.PagesFrom,
+			PagesTo:   options.PagesTo,
+			RunID:     options.RunID},
+		Limiter: steamerLimiter,
+		Log: &SteamerLog{
+			Depth:     options.Depth,
+			PagesFrom: options.PagesFrom,
+			PagesTo:   options.PagesTo,
+			PagesOK:   NewSteamerLogPageOK(),
+			RunID:     options.RunID,
+			TimeStart: time.Now()},
+		Options: options,
+		Output:  os.Stdout,
+		Store:   store,
+		Summary: &SteamerSummary{
+			Developers: make(map[string][]int),
+			Games:      0,
+			Genres:     make(map[string]int),
+			PagesFrom:  options.PagesFrom,
+			PagesTo:    options.PagesTo,
+			Publishers: make(map[string][]int),
+			Sentiments: make(map[string]int),
+			Titles:     make(map[int]string)},
+		SummaryCSV: []SteamSummaryCSV{},
+		claimed:    map[string]bool{},
+		cond:       sync.NewCond(mu),
+		mu:         mu,
+		wg:         &sync.WaitGroup{}}
+}
+
+func (crawlerOptions *CrawlerOptions) Validate() error {
+	if err := crawlerOptions.validateSearch(); err != nil {
+		return err
+	}
+	if crawlerOptions.Revisit != nil {
+		if err := crawlerOptions.Revisit.Validate(); err != nil {
+			return err
+		}
+	}
+	switch crawlerOptions.Depth {
+	case "", SteamerStageChart:
+		return nil
+	case SteamerStageGame:
+	case SteamerStageSearch:
+		if crawlerOptions.Revisit != nil && (crawlerOptions.Revisit.Game || crawlerOptions.Revisit.GameAfter > 0) {
+			return errors.New("CrawlerOptions.Revisit.Game requires Depth game or chart")
+		}
+		if crawlerOptions.Write != nil && crawlerOptions.Write.Game {
+			return errors.New("CrawlerOptions.Write.Game requires Depth game or chart")
+		}
+	default:
+		return fmt.Errorf("CrawlerOptions.Depth %q unknown", crawlerOptions.Depth)
+	}
+	if crawlerOptions.Revisit != nil && (crawlerOptions.Revisit.Chart || crawlerOptions.Revisit.ChartAfter > 0) {
+		return errors.New("CrawlerOptions.Revisit.Chart requires Depth chart")
+	}
+	if crawlerOptions.Write != nil && crawlerOptions.Write.Chart {
+		return errors.New("CrawlerOptions.Write.Chart requires Depth chart")
+	}
+	return nil
+}
+
+func (crawlerOptions *CrawlerOptions) validateSearch() error {
+	switch crawlerOptions.SearchMode {
+	case "", SteamerSearchInfinite, SteamerSearchPage:
+	default:
+		return fmt.Errorf("CrawlerOptions.SearchMode %q unknown", crawlerOptions.SearchMode)
+	}
+	if crawlerOptions.SearchCount < 0 {
+		return errors.New("CrawlerOptions.SearchCount is negative")
+	}
+	return nil
+}
+
+func (crawler *Crawler) Run(ctx context.Context) error {
+	workers := &sync.WaitGroup{}
+	for i := 0; i < crawler.Options.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			crawler.work(ctx)
+		}()
+	}
+	if crawler.resume != nil {
+		var steamerTasks []SteamerTask
+		for _, task := range crawler.resume {
+			if ok := crawler.claim(task); ok != true {
+				continue
+			}
+			// resumed search pages are already journaled and keep stopping at the first empty page
+			if crawler.Options.TerminateZero && task.Stage == SteamerStageSearch {
+				steamerTasks = append(steamerTasks, task)
+				continue
+			}
+			crawler.dispatch(ctx, task)
+		}
+		sort.SliceStable(steamerTasks, func(i, j int) bool {
+			return steamerTasks[i].Page < steamerTasks[j].Page
+		})
+		crawler.runTerminateZero(ctx, steamerTasks)
+	} else {
+		pagesFrom := crawler.Options.PagesFrom
+		if crawler.Options.PagesAll {
+			pagesFrom = crawler.discover(ctx)
+		}
+		if crawler.Options.TerminateZero {
+			var steamerTasks []SteamerTask
+			// the whole range is journaled before the first fetch so -resume can continue a crash or Ctrl-C
+			for i := pagesFrom; i <= crawler.Options.PagesTo; i++ {
+				task := NewSteamerTask(SteamerStageSearch, crawler.searchURL(i), i, nil)
+				if ok := crawler.claim(task); ok != true {
+					continue
+				}
+				crawler.Journal.Queue(task)
+				steamerTasks = append(steamerTasks, task)
+			}
+			crawler.runTerminateZero(ctx, steamerTasks)
+		} else {
+			for i := pagesFrom; i <= crawler.Options.PagesTo; i++ {
+				crawler.schedule(ctx, NewSteamerTask(SteamerStageSearch, crawler.searchURL(i), i, nil))
+			}
+		}
+	}
+	crawler.wg.Wait()
+	crawler.mu.Lock()
+	crawler.stopped = true
+	crawler.mu.Unlock()
+	crawler.cond.Broadcast()
+	workers.Wait()
+	crawler.Log.Incomplete = (ctx.Err() != nil)
+	crawler.Log.TimeEnd = time.Now()
+	crawler.Log.TimeDuration = crawler.Log.TimeEnd.Sub(crawler.Log.TimeStart)
+	crawler.Summary.Incomplete = crawler.Log.Incomplete
+	crawler.Log.Failures = len(crawler.DeadLetter.Failures)
+	var errs []error
+	if crawler.Options.Write.Log {
+		if err := crawler.Store.WriteSteamerLog(crawler.Log); err != nil {
+			errs = append(errs, fmt.Errorf("SteamerLog: %w", err))
+		}
+	}
+	if err := crawler.Store.WriteSteamerDeadLetter(crawler.DeadLetter); err != nil {
+		errs = append(errs, fmt.Errorf("SteamerDeadLetter: %w", err))
+	}
+	if crawler.Options.Write.Summary {
+		if err := crawler.Store.WriteSteamerSummary(crawler.Summary); err != nil {
+			errs = append(errs, fmt.Errorf("SteamerSummary: %w", err))
+		}
+	}
+	// a run that found nothing has no rows to write, which is not a failure
+	if crawler.Options.Write.CSV && len(crawler.SummaryCSV) > 0 {
+		filename := fmt.Sprintf("%d-%d-%d-summary.csv", time.Now().UnixNano(), crawler.Options.PagesFrom, crawler.Options.PagesTo)
+		if crawler.Log.Incomplete {
+			filename = fmt.Sprintf("%d-%d-%d-summary-incomplete.csv", time.Now().UnixNano(), crawler.Options.PagesFrom, crawler.Options.PagesTo)
+		}
+		if err := crawler.Store.WriteSteamSummaryCSV(filename, &crawler.SummaryCSV); err != nil {
+			errs = append(errs, fmt.Errorf("SteamSummaryCSV: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (crawler *Crawler) Resume(steamerJournalEntries []SteamerJournalEntry) {
+	pagesFrom, pagesTo := 0, 0
+	for _, steamerJournalEntry := range steamerJournalEntries {
+		if steamerJournalEntry.Stage != SteamerStageSearch {
+			continue
+		}
+		if pagesFrom == 0 || steamerJournalEntry.Page < pagesFrom {
+			pagesFrom = steamerJournalEntry.Page
+		}
+		if steamerJournalEntry.Page > pagesTo {
+			pagesTo = steamerJournalEntry.Page
+		}
+	}
+	if pagesFrom > 0 {
+		crawler.setPages(pagesFrom, pagesTo)
+	}
+	for _, steamerJournalEntry := range steamerJournalEntries {
+		crawler.claimed[steamerJournalEntry.Key()] = (isSteamerJournalPending(steamerJournalEntry) != true)
+	}
+	var steamerTasks []SteamerTask
+	for _, steamerJournalEntry := range PendingSteamerJournal(steamerJournalEntries) {
+		steamerTasks = append(steamerTasks, steamerJournalEntry.SteamerTask)
+	}
+	crawler.requeue(steamerTasks)
+}
+
+func (crawler *Crawler) RetryFailed(steamerDeadLetter *SteamerDeadLetter) {
+	crawler.setPages(steamerDeadLetter.PagesFrom, steamerDeadLetter.PagesTo)
+	crawler.requeue(steamerDeadLetter.Tasks())
+}
+
+func (crawler *Crawler) discover(ctx context.Context) int {
+	pagesFrom := crawler.Options.PagesFrom
+	task := NewSteamerTask(SteamerStageSearch, crawler.searchURL(pagesFrom), pagesFrom, nil)
+	crawler.discovery = task.Key()
+	if ok := crawler.claim(task); ok != true {
+		return pagesFrom
+	}
+	crawler.Journal.Queue(task)
+	crawler.run(ctx, task)
+	if crawler.Options.TerminateZero && crawler.isZeroPage(pagesFrom) {
+		crawler.Log.TerminateZero = true
+		crawler.setPages(pagesFrom, pagesFrom)
+		return pagesFrom + 1
+	}
+	crawler.mu.Lock()
+	pagesLast := crawler.Log.PagesLast
+	crawler.mu.Unlock()
+	// nothing matched, the first page failed or -from is past the end; the remaining range would only repeat the miss
+	if pagesLast < pagesFrom {
+		pagesLast = pagesFrom
+	}
+	crawler.setPages(pagesFrom, pagesLast)
+	// the first page has been crawled, so the remaining range starts after it and is empty when it was the last
+	return pagesFrom + 1
+}
+
+func (crawler *Crawler) isZeroPage(page int) bool {
+	steamerLogPage, _ := crawler.Log.PagesOK.Get(page)
+	// a page of bundles and packages still has rows, so only a page without any row ends the crawl
+	return steamerLogPage.StatusCode == http.StatusOK && steamerLogPage.GamesFound == 0 && steamerLogPage.GamesSkipped == 0
+}
+
+func (crawler *Crawler) runTerminateZero(ctx context.Context, steamerTasks []SteamerTask) {
+	for i, task := range steamerTasks {
+		crawler.run(ctx, task)
+		if ctx.Err() != nil {
+			// pages that never ran stay queued in the journal for -resume and go to the dead letter
+			for _, task := range steamerTasks[i+1:] {
+				crawler.fail(task, nil, ctx.Err())
+			}
+			return
+		}
+		if crawler.isZeroPage(task.Page) {
+			crawler.Log.TerminateZero = true
+			for _, task := range steamerTasks[i+1:] {
+				crawler.Journal.Skipped(task)
+			}
+			return
+		}
+	}
+}
+
+func (crawler *Crawler) setPagination(steamSearchPagination SteamSearchPagination) {
+	crawler.mu.Lock()
+	defer crawler.mu.Unlock()
+	crawler.Log.TotalCount = steamSearchPagination.TotalCount
+	if steamSearchPagination.LastPage > crawler.Log.PagesLast {
+		crawler.Log.PagesLast = steamSearchPagination.LastPage
+	}
+}
+
+func (crawler *Crawler) setPages(pagesFrom, pagesTo int) {
+	crawler.Options.PagesFrom, crawler.Options.PagesTo = pagesFrom, pagesTo
+	crawler.DeadLetter.PagesFrom, crawler.DeadLetter.PagesTo = pagesFrom, pagesTo
+	crawler.Log.PagesFrom, crawler.Log.PagesTo = pagesFrom, pagesTo
+	crawler.Summary.PagesFrom, crawler.Summary.PagesTo = pagesFrom, pagesTo
+}
+
+func (crawler *Crawler) searchURL(page int) string {
+	if crawler.Options.SearchMode == SteamerSearchInfinite {
+		return NewSteamSearchResultsURL(crawler.Options.PageQuery, page, crawler.Options.SearchCount)
+	}
+	return NewSteamSearchURL(crawler.Options.PageQuery, page)
+}
+
+func (crawler *Crawler) claim(task SteamerTask) bool {
+	crawler.mu.Lock()
+	defer crawler.mu.Unlock()
+	key := task.Key()
+	if crawler.claimed[key] {
+		return false
+	}
+	crawler.claimed[key] = true
+	return true
+}
+
+func (crawler *Crawler) dispatch(ctx context.Context, task SteamerTask) {
+	crawler.wg.Add(1)
+	crawler.mu.Lock()
+	crawler.queue = append(crawler.queue, task)
+	crawler.mu.Unlock()
+	crawler.cond.Signal()
+}
+
+func (crawler *Crawler) work(ctx context.Context) {
+	for {
+		crawler.mu.Lock()
+		for len(crawler.queue) == 0 && crawler.stopped != true {
+			crawler.cond.Wait()
+		}
+		if len(crawler.queue) == 0 {
+			crawler.mu.Unlock()
+			return
+		}
+		task := crawler.queue[0]
+		crawler.queue = crawler.queue[1:]
+		crawler.mu.Unlock()
+		crawler.run(ctx, task)
+		crawler.wg.Done()
+	}
+}
+
+func (crawler *Crawler) requeue(steamerTasks []SteamerTask) {
+	crawler.resume = []SteamerTask{}
+	crawler.resumed = map[string]bool{}
+	for _, task := range steamerTasks {
+		crawler.resume = append(crawler.resume, task)
+		crawler.resumed[task.Key()] = true
+	}
+}
+
+func (crawler *Crawler) revisit(task SteamerTask, revisit bool, after SteamerDuration) bool {
+	// the discovery page is always fetched because a skipped page cannot report the page range
+	if revisit || crawler.resumed[task.Key()] || task.Key() == crawler.discovery {
+		return true
+	}
+	if after <= 0 {
+		return false
+	}
+	u, err := url.Parse(task.URL)
+	if err != nil {
+		return false
+	}
+	timeEnd, ok, err := crawler.Store.VisitedURL(u)
+	if err != nil || ok != true {
+		return false
+	}
+	return time.Since(timeEnd) >= time.Duration(after)
+}
+
+func (crawler *Crawler) snapshot(ctx context.Context, URL string, revisit bool, HTTPCookies *[]*http.Cookie) *Snapshot {
+	// pages not due for a revisit are served from the store, so they still reach the summary and the next stage
+	if revisit == false {
+		if replayStore, ok := crawler.Store.(ReplayStore); ok {
+			if snapshot, err := NewStoredSnapshot(replayStore, URL); err == nil {
+				return snapshot
+			}
+		}
+	}
+	return NewSnapshot(ctx, crawler.Client, crawler.Options.Retry, http.MethodGet, URL, HTTPCookies)
+}
+
+func (crawler *Crawler) run(ctx context.Context, task SteamerTask) {
+	if ctx.Err() != nil {
+		crawler.fail(task, nil, ctx.Err())
+		return
+	}
+	var err error
+	switch task.Stage {
+	case SteamerStageSearch:
+		err = crawler.crawlSearchPage(ctx, task)
+	case SteamerStageGame:
+		err = crawler.crawlGamePage(ctx, task)
+	case SteamerStageChart:
+		err = crawler.crawlChartPage(ctx, task)
+	}
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		crawler.Journal.Failed(task, err)
+		return
+	}
+	crawler.Journal.Done(task)
+}
+
+func (crawler *Crawler) schedule(ctx context.Context, task SteamerTask) {
+	if ok := crawler.claim(task); ok != true {
+		return
+	}
+	if ctx.Err() != nil {
+		crawler.fail(task, nil, ctx.Err())
+		return
+	}
+	crawler.Journal.Queue(task)
+	crawler.dispatch(ctx, task)
+}
+
+func (crawler *Crawler) crawlSearchPage(ctx context.Context, task SteamerTask) error {
+	var (
+		failure  error
+		found    int
+		skipped  int
+		snapshot *Snapshot
+	)
+	revisit := crawler.revisit(task, crawler.Options.Revisit.Search, crawler.Options.Revisit.SearchAfter)
+	snap := func(s *Snapshot) {
+		snapshot = s
+		crawler.onSnapshot(task, s, "[PAGE]")
+	}
+	fail := func(e error) {
+		failure = e
+		crawler.fail(task, snapshot, e)
+	}
+	success := func(s *SteamGameAbbreviation) {
+		if crawler.Options.Write.Abbreviation {
+			if err := crawler.Store.WriteSteamGameAbbreviation(s); err != nil {
+				fail(err)
+			}
+		}
+		found = found + 1
+		steamerTask := NewSteamerTask(SteamerStageGame, s.URL, task.Page, nil)
+		if crawler.Options.Depth != SteamerStageSearch {
+			crawler.schedule(ctx, steamerTask)
+		} else if ok := crawler.claim(steamerTask); ok {
+			if err := crawler.addSteamGameSummary(NewSteamGameAbbreviationSummary(s)); err != nil {
+				fail(err)
+			}
+		}
+	}
+	skip := func(s *SteamGameAbbreviation) {
+		skipped = skipped + 1
+	}
+	if crawler.Options.SearchMode == SteamerSearchInfinite {
+		crawler.onGetSteamSearchResults(ctx, task.URL, revisit, snap,
+			func(s *SteamSearchResults) {
+				crawler.setPagination(NewSteamSearchResultsPagination(s, crawler.Options.SearchCount))
+			}, success, skip, fail)
+	} else {
+		crawler.onGetSteamGameAbbreviation(ctx, task.URL, revisit, snap, success, skip, fail)
+		if snapshot != nil && snapshot.StatusCode == http.StatusOK && snapshot.Document() != nil {
+			crawler.setPagination(NewSteamSearchPagination(snapshot.Document().Selection))
+		}
+	}
+	if ctx.Err() != nil {
+		return failure
+	}
+	if snapshot != nil {
+		crawler.Log.PagesOK.AddStatusCode(task.Page, snapshot.StatusCode)
+	}
+	crawler.Log.PagesOK.AddGamesFound(task.Page, found)
+	crawler.Log.PagesOK.AddGamesSkipped(task.Page, skipped)
+	crawler.Log.PagesOK.Add(task.Page, failure == nil)
+	return failure
+}
+
+func (crawler *Crawler) crawlGamePage(ctx context.Context, task SteamerTask) error {
+	var (
+		failure  error
+		snapshot *Snapshot
+	)
+	revisit := crawler.revisit(task, crawler.Options.Revisit.Game, crawler.Options.Revisit.GameAfter)
+	crawler.onGetSteamGamePage(ctx, task.URL, revisit,
+		func(s *Snapshot) {
+			snapshot = s
+			crawler.onSnapshot(task, s, "[GAME]")
+		},
+		func(s *SteamGamePage) {
+			if crawler.Options.Write.Game {
+				if err := crawler.Store.WriteSteamGamePage(s); err != nil {
+					failure = err
+					crawler.fail(task, snapshot, err)
+				}
+			}
+			if crawler.Options.Depth == SteamerStageGame {
+				if err := crawler.addSteamGameSummary(NewSteamGameSummary(s, nil)); err != nil {
+					failure = err
+					crawler.fail(task, snapshot, err)
+				}
+			} else {
+				crawler.schedule(ctx, NewSteamerTask(SteamerStageChart, fmt.Sprintf("https://%s/app/%d", SteamChartsHost, s.AppID), task.Page, s))
+			}
+			crawler.addSteamerSummary(s)
+			crawler.Log.PagesOK.AddGamesFetched(task.Page)
+		},
+		func(e error) {
+			failure = e
+			crawler.fail(task, snapshot, e)
+		})
+	return failure
+}
+
+func (crawler *Crawler) crawlChartPage(ctx context.Context, task SteamerTask) error {
+	var (
+		failure  error
+		snapshot *Snapshot
+	)
+	if task.Game == nil {
+		failure = errors.New("SteamerTask.Game empty")
+		crawler.fail(task, nil, failure)
+		crawler.Log.PagesOK.AddChart(task.Page, false)
+		return failure
+	}
+	revisit := crawler.revisit(task, crawler.Options.Revisit.Chart, crawler.Options.Revisit.ChartAfter)
+	crawler.onGetSteamChartPage(ctx, task.URL, revisit,
+		func(s *Snapshot) {
+			snapshot = s
+			crawler.onSnapshot(task, s, "[CHART]")
+		},
+		func(s *SteamChartPage) {
+			if crawler.Options.Write.Chart {
+				if err := crawler.Store.WriteSteamChartPage(s); err != nil {
+					failure = err
+					crawler.fail(task, snapshot, err)
+				}
+			}
+			if err := crawler.addSteamGameSummary(NewSteamGameSummary(task.Game, s)); err != nil {
+				failure = err
+				crawler.fail(task, snapshot, err)
+			}
+			crawler.Log.PagesOK.AddChart(task.Page, failure == nil)
+		},
+		func(e error) {
+			failure = e
+			crawler.fail(task, snapshot, e)
+			if ctx.Err() == nil {
+				crawler.Log.PagesOK.AddChart(task.Page, false)
+			}
+		})
+	return failure
+}
+
+func (crawler *Crawler) fail(task SteamerTask, snapshot *Snapshot, err error) {
+	// cancelled tasks are kept as well, so retry-failed can still reach what an interrupted run never finished
+	crawler.mu.Lock()
+	defer crawler.mu.Unlock()
+	crawler.DeadLetter.Failures = append(crawler.DeadLetter.Failures, NewSteamerFailure(task, snapshot, err))
+}
+
+func (crawler *Crawler) onSnapshot(task SteamerTask, s *Snapshot, stage string) {
+	if crawler.Options.Verbose {
+		var host string
+		if u, err := url.Parse(s.URL); err == nil {
+			host = u.Hostname()
+		}
+		fmt.Fprintln(crawler.Output, "URL", "\t", "->", stage, s.URL, "\t", "queue", "->", crawler.Limiter.Queue(host), "active", "->", crawler.Limiter.Active(host))
+	}
+	if s.Cache == SnapshotCacheStored {
+		crawler.mu.Lock()
+		crawler.Log.Stored++
+		crawler.mu.Unlock()
+		return
+	}
+	if crawler.WARC != nil && s.response != nil {
+		if err := crawler.WARC.Write(s); err != nil {
+			crawler.mu.Lock()
+			crawler.Log.WARCFailures++
+			crawler.mu.Unlock()
+			fmt.Fprintln(crawler.Output, "warc", "\t", "->", s.URL, err)
+		}
+	}
+	if crawler.Options.Write.Snapshot {
+		crawler.wg.Add(1)
+		go func(s *Snapshot) {
+			defer crawler.wg.Done()
+			if err := crawler.Store.WriteSnapshot(s); err != nil {
+				crawler.fail(task, s, err)
+				return
+			}
+			// a visit is only kept once the snapshot it points at is stored
+			if err := crawler.Store.WriteVisit(s); err != nil {
+				fmt.Fprintln(crawler.Output, "visit", "\t", "->", s.URL, err)
+			}
+		}(s)
+	}
+}
+
+func (crawler *Crawler) addSteamGameSummary(s *SteamGameSummary) error {
+	var err error
+	if crawler.Options.Write.GameSummary {
+		err = crawler.Store.WriteSteamGameSummary(s)
+	}
+	crawler.mu.Lock()
+	defer crawler.mu.Unlock()
+	crawler.SummaryCSV = append(crawler.SummaryCSV, NewSteamSummaryCSV(s))
+	return err
+}
+
+func (crawler *Crawler) addSteamerSummary(s *SteamGamePage) {
+	crawler.mu.Lock()
+	defer crawler.mu.Unlock()
+	steamerSummary := crawler.Summary
+	if _, ok := steamerSummary.Titles[s.AppID]; ok {
+		return
+	}
+	steamerSummary.Games = steamerSummary.Games + 1
+	steamerSummary.Titles[s.AppID] = s.Title
+	for _, x := range s.Developers {
+		steamerSummary.Developers[x.Name] = append(steamerSummary.Developers[x.Name], s.AppID)
+	}
+	for _, x := range s.Genres {
+		steamerSummary.Genres[x.Name] = steamerSummary.Genres[x.Name] + 1
+	}
+	for _, x := range s.Publishers {
+		steamerSummary.Publishers[x.Name] = append(steamerSummary.Publishers[x.Name], s.AppID)
+	}
+}
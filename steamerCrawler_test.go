@@ -0,0 +1,409 @@
+package steamer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// steamerTestTransport serves search pages, game pages and chart pages for the app IDs on each search page
+type steamerTestTransport struct {
+	active   int
+	max      int
+	mu       sync.Mutex
+	pages    map[int][]int
+	requests map[string]int
+	wait     time.Duration
+}
+
+func newSteamerTestTransport(pages map[int][]int) *steamerTestTransport {
+	return &steamerTestTransport{
+		pages:    pages,
+		requests: map[string]int{}}
+}
+
+func (steamerTestTransport *steamerTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	steamerTestTransport.mu.Lock()
+	steamerTestTransport.requests[req.URL.Host+req.URL.Path]++
+	steamerTestTransport.active++
+	if steamerTestTransport.active > steamerTestTransport.max {
+		steamerTestTransport.max = steamerTestTransport.active
+	}
+	steamerTestTransport.mu.Unlock()
+	time.Sleep(steamerTestTransport.wait)
+	defer func() {
+		steamerTestTransport.mu.Lock()
+		steamerTestTransport.active--
+		steamerTestTransport.mu.Unlock()
+	}()
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("Content-Type", "text/html; charset=utf-8")
+	appID := parseSteamAppID(req.URL.Path)
+	switch {
+	case req.URL.Host == SteamStoreHost && req.URL.Path == "/search/":
+		var page int
+		fmt.Sscanf(req.URL.Query().Get("page"), "%d", &page)
+		fmt.Fprintf(recorder, `<html><body><div class="search_pagination_left">showing of %d</div><div class="search_pagination_right">`, steamerTestTransport.total())
+		for i := range steamerTestTransport.pages {
+			fmt.Fprintf(recorder, `<a>%d</a>`, i)
+		}
+		fmt.Fprint(recorder, `</div>`)
+		fmt.Fprint(recorder, steamerTestTransport.rows(page))
+		fmt.Fprint(recorder, `</body></html>`)
+	case req.URL.Host == SteamStoreHost && req.URL.Path == "/search/results/":
+		var start, count int
+		fmt.Sscanf(req.URL.Query().Get("start"), "%d", &start)
+		fmt.Sscanf(req.URL.Query().Get("count"), "%d", &count)
+		recorder.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(recorder).Encode(SteamSearchResults{
+			ResultsHTML: steamerTestTransport.rows(start/count + 1),
+			Start:       start,
+			Success:     1,
+			TotalCount:  steamerTestTransport.total()})
+	case req.URL.Host == SteamStoreHost && appID > -1:
+		fmt.Fprintf(recorder, `<html><body><div data-appid="%d"></div><div class="apphub_AppName">App %d</div></body></html>`, appID, appID)
+	case req.URL.Host == SteamChartsHost && appID > -1:
+		fmt.Fprint(recorder, `<html><body></body></html>`)
+	default:
+		recorder.WriteHeader(http.StatusNotFound)
+	}
+	res := recorder.Result()
+	res.Request = req
+	return res, nil
+}
+
+func (steamerTestTransport *steamerTestTransport) rows(page int) string {
+	var b strings.Builder
+	for _, appID := range steamerTestTransport.pages[page] {
+		fmt.Fprintf(&b, `<a class="search_result_row" href="https://%s/app/%d/" data-ds-appid="%d"><span class="title">App %d</span></a>`, SteamStoreHost, appID, appID, appID)
+	}
+	return b.String()
+}
+
+func (steamerTestTransport *steamerTestTransport) total() int {
+	var total int
+	for _, appIDs := range steamerTestTransport.pages {
+		total = total + len(appIDs)
+	}
+	return total
+}
+
+func (steamerTestTransport *steamerTestTransport) Requests(host string) int {
+	steamerTestTransport.mu.Lock()
+	defer steamerTestTransport.mu.Unlock()
+	var n int
+	for key, count := range steamerTestTransport.requests {
+		if strings.HasPrefix(key, host) {
+			n = n + count
+		}
+	}
+	return n
+}
+
+func newSteamerTestCrawler(transport http.RoundTripper, store Store, options *CrawlerOptions) *Crawler {
+	crawler := NewCrawler(&http.Client{Transport: transport}, store, options)
+	crawler.Output = ioutil.Discard
+	return crawler
+}
+
+func TestCrawlerRevisitServesStoredPages(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		store func(fullpath, runID string) (Store, error)
+	}{
+		{"file", func(fullpath, runID string) (Store, error) {
+			return NewFileStore(fullpath), nil
+		}},
+		{"sqlite", func(fullpath, runID string) (Store, error) {
+			return NewSQLiteStore(filepath.Join(fullpath, "steamer.db"), runID)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fullpath := t.TempDir()
+			transport := newSteamerTestTransport(map[int][]int{1: {10, 20}})
+			var crawlers []*Crawler
+			for _, runID := range []string{"run-1", "run-2"} {
+				store, err := test.store(fullpath, runID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				crawler := newSteamerTestCrawler(transport, store, &CrawlerOptions{
+					RunID: runID,
+					Write: NewSteamerWrite(1)})
+				if err := crawler.Run(context.Background()); err != nil {
+					t.Fatalf("%s: Crawler.Run: %v", runID, err)
+				}
+				if closer, ok := store.(interface{ Close() error }); ok {
+					closer.Close()
+				}
+				crawlers = append(crawlers, crawler)
+			}
+			if got := transport.Requests(SteamStoreHost); got != 3 {
+				t.Errorf("store requests = %d, want 3", got)
+			}
+			if got := transport.Requests(SteamChartsHost); got != 2 {
+				t.Errorf("chart requests = %d, want 2", got)
+			}
+			if got := crawlers[1].Log.Stored; got != 5 {
+				t.Errorf("second run Log.Stored = %d, want 5", got)
+			}
+			for i, crawler := range crawlers {
+				if got := len(crawler.SummaryCSV); got != 2 {
+					t.Errorf("run %d: len(SummaryCSV) = %d, want 2", i+1, got)
+				}
+				if got := crawler.Summary.Games; got != 2 {
+					t.Errorf("run %d: Summary.Games = %d, want 2", i+1, got)
+				}
+				if got := len(crawler.DeadLetter.Failures); got != 0 {
+					t.Errorf("run %d: %d failures, want 0", i+1, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCrawlerTerminateZero(t *testing.T) {
+	fullpath := t.TempDir()
+	steamerJournal, err := NewSteamerJournal(filepath.Join(fullpath, "journal.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := newSteamerTestTransport(map[int][]int{1: {10}, 2: {20}, 3: {}, 4: {40}})
+	crawler := newSteamerTestCrawler(transport, NewFileStore(fullpath), &CrawlerOptions{
+		Depth:         SteamerStageSearch,
+		PagesTo:       5,
+		TerminateZero: true})
+	crawler.Journal = steamerJournal
+	if err := crawler.Run(context.Background()); err != nil {
+		t.Fatalf("Crawler.Run: %v", err)
+	}
+	steamerJournal.Close()
+	if crawler.Log.TerminateZero != true {
+		t.Error("Log.TerminateZero = false, want true")
+	}
+	if got := len(crawler.SummaryCSV); got != 2 {
+		t.Errorf("len(SummaryCSV) = %d, want 2", got)
+	}
+	// the empty page ends the crawl and is not a failure
+	if got := len(crawler.DeadLetter.Failures); got != 0 {
+		t.Errorf("%d failures, want 0: %v", got, crawler.DeadLetter.Failures)
+	}
+	if got := transport.Requests(SteamStoreHost + "/search/"); got != 3 {
+		t.Errorf("search requests = %d, want 3", got)
+	}
+	steamerJournalEntries, err := ReadSteamerJournal(steamerJournal.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := map[int]string{}
+	for _, steamerJournalEntry := range steamerJournalEntries {
+		status[steamerJournalEntry.Page] = steamerJournalEntry.Status
+	}
+	for page, want := range map[int]string{1: SteamerJournalDone, 2: SteamerJournalDone, 3: SteamerJournalDone, 4: SteamerJournalSkipped, 5: SteamerJournalSkipped} {
+		if status[page] != want {
+			t.Errorf("page %d journal status = %q, want %q", page, status[page], want)
+		}
+	}
+}
+
+func TestCrawlerEmptyRun(t *testing.T) {
+	crawler := newSteamerTestCrawler(newSteamerTestTransport(map[int][]int{1: {}}), NewFileStore(t.TempDir()), &CrawlerOptions{
+		Depth: SteamerStageSearch})
+	if err := crawler.Run(context.Background()); err != nil {
+		t.Errorf("Crawler.Run = %v, want nil", err)
+	}
+	if got := len(crawler.DeadLetter.Failures); got != 0 {
+		t.Errorf("%d failures, want 0", got)
+	}
+}
+
+func TestCrawlerWorkers(t *testing.T) {
+	appIDs := []int{}
+	for i := 1; i <= 10; i++ {
+		appIDs = append(appIDs, i*10)
+	}
+	transport := newSteamerTestTransport(map[int][]int{1: appIDs})
+	transport.wait = 5 * time.Millisecond
+	crawler := newSteamerTestCrawler(transport, NewFileStore(t.TempDir()), &CrawlerOptions{
+		Depth:   SteamerStageGame,
+		Workers: 2})
+	if err := crawler.Run(context.Background()); err != nil {
+		t.Fatalf("Crawler.Run: %v", err)
+	}
+	if got := len(crawler.SummaryCSV); got != 10 {
+		t.Errorf("len(SummaryCSV) = %d, want 10", got)
+	}
+	if transport.max > 2 {
+		t.Errorf("max concurrent requests = %d, want at most 2 workers", transport.max)
+	}
+}
+
+func TestCrawlerDepth(t *testing.T) {
+	tests := []struct {
+		depth  string
+		games  int
+		charts int
+	}{
+		{SteamerStageSearch, 0, 0},
+		{SteamerStageGame, 2, 0},
+		{SteamerStageChart, 2, 2},
+	}
+	for _, test := range tests {
+		transport := newSteamerTestTransport(map[int][]int{1: {10, 20}})
+		crawler := newSteamerTestCrawler(transport, NewFileStore(t.TempDir()), &CrawlerOptions{
+			Depth: test.depth})
+		if err := crawler.Run(context.Background()); err != nil {
+			t.Fatalf("%s: Crawler.Run: %v", test.depth, err)
+		}
+		if got := transport.Requests(SteamStoreHost + "/app/"); got != test.games {
+			t.Errorf("%s: game requests = %d, want %d", test.depth, got, test.games)
+		}
+		if got := transport.Requests(SteamChartsHost); got != test.charts {
+			t.Errorf("%s: chart requests = %d, want %d", test.depth, got, test.charts)
+		}
+		// every depth still writes one summary row per game found on the search page
+		if got := len(crawler.SummaryCSV); got != 2 {
+			t.Errorf("%s: len(SummaryCSV) = %d, want 2", test.depth, got)
+		}
+	}
+}
+
+func TestCrawlerOptionsValidateDepth(t *testing.T) {
+	tests := []struct {
+		crawlerOptions *CrawlerOptions
+		ok             bool
+	}{
+		{&CrawlerOptions{Depth: SteamerStageGame, Write: &SteamerWrite{Game: true}}, true},
+		{&CrawlerOptions{Depth: "store"}, false},
+		{&CrawlerOptions{Depth: SteamerStageSearch, Write: &SteamerWrite{Game: true}}, false},
+		{&CrawlerOptions{Depth: SteamerStageGame, Revisit: &SteamerRevisit{Chart: true}}, false},
+	}
+	for _, test := range tests {
+		if err := test.crawlerOptions.Validate(); (err == nil) != test.ok {
+			t.Errorf("CrawlerOptions{Depth: %q}.Validate() = %v, want ok %v", test.crawlerOptions.Depth, err, test.ok)
+		}
+	}
+}
+
+func TestCrawlerResume(t *testing.T) {
+	fullpath := t.TempDir()
+	name := filepath.Join(fullpath, "journal.jsonl")
+	steamerJournal, err := NewSteamerJournal(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an interrupted run: page 1 and its first game finished, its second game and page 2 never ran
+	page1 := NewSteamerTask(SteamerStageSearch, NewSteamSearchURL("", 1), 1, nil)
+	page2 := NewSteamerTask(SteamerStageSearch, NewSteamSearchURL("", 2), 2, nil)
+	game10 := NewSteamerTask(SteamerStageGame, fmt.Sprintf("https://%s/app/10/", SteamStoreHost), 1, nil)
+	game20 := NewSteamerTask(SteamerStageGame, fmt.Sprintf("https://%s/app/20/", SteamStoreHost), 1, nil)
+	for _, task := range []SteamerTask{page1, page2, game10, game20} {
+		steamerJournal.Queue(task)
+	}
+	steamerJournal.Done(page1)
+	steamerJournal.Done(game10)
+	steamerJournal.Close()
+	steamerJournalEntries, err := ReadSteamerJournal(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steamerJournal, err = NewSteamerJournal(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := newSteamerTestTransport(map[int][]int{1: {10, 20}, 2: {30}})
+	crawler := newSteamerTestCrawler(transport, NewFileStore(fullpath), &CrawlerOptions{
+		Depth: SteamerStageGame})
+	crawler.Journal = steamerJournal
+	crawler.Resume(steamerJournalEntries)
+	if err := crawler.Run(context.Background()); err != nil {
+		t.Fatalf("Crawler.Run: %v", err)
+	}
+	steamerJournal.Close()
+	if crawler.Options.PagesFrom != 1 || crawler.Options.PagesTo != 2 {
+		t.Errorf("pages = %d-%d, want 1-2", crawler.Options.PagesFrom, crawler.Options.PagesTo)
+	}
+	for key, want := range map[string]int{
+		SteamStoreHost + "/search/": 1,
+		SteamStoreHost + "/app/10/": 0,
+		SteamStoreHost + "/app/20/": 1,
+		SteamStoreHost + "/app/30/": 1,
+	} {
+		if got := transport.Requests(key); got != want {
+			t.Errorf("%s requests = %d, want %d", key, got, want)
+		}
+	}
+	steamerJournalEntries, err = ReadSteamerJournal(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending := PendingSteamerJournal(steamerJournalEntries); len(pending) != 0 {
+		t.Errorf("pending = %d entries after the resumed run, want 0", len(pending))
+	}
+}
+
+func TestCrawlerPagesAll(t *testing.T) {
+	tests := []struct {
+		pagesFrom int
+		searches  int
+		games     int
+	}{
+		{1, 3, 3},
+		{2, 2, 2},
+		// -from past the last page stops after the one page it fetched
+		{5, 1, 0},
+	}
+	for _, test := range tests {
+		transport := newSteamerTestTransport(map[int][]int{1: {10}, 2: {20}, 3: {30}})
+		crawler := newSteamerTestCrawler(transport, NewFileStore(t.TempDir()), &CrawlerOptions{
+			Depth:     SteamerStageSearch,
+			PagesAll:  true,
+			PagesFrom: test.pagesFrom})
+		if err := crawler.Run(context.Background()); err != nil {
+			t.Fatalf("from %d: Crawler.Run: %v", test.pagesFrom, err)
+		}
+		if got := transport.Requests(SteamStoreHost + "/search/"); got != test.searches {
+			t.Errorf("from %d: search requests = %d, want %d", test.pagesFrom, got, test.searches)
+		}
+		if got := len(crawler.SummaryCSV); got != test.games {
+			t.Errorf("from %d: len(SummaryCSV) = %d, want %d", test.pagesFrom, got, test.games)
+		}
+		pagesTo := test.pagesFrom + test.searches - 1
+		if crawler.Log.PagesFrom != test.pagesFrom || crawler.Log.PagesTo != pagesTo {
+			t.Errorf("from %d: Log pages = %d-%d, want %d-%d", test.pagesFrom, crawler.Log.PagesFrom, crawler.Log.PagesTo, test.pagesFrom, pagesTo)
+		}
+	}
+}
+
+func TestCrawlerSearchInfinite(t *testing.T) {
+	transport := newSteamerTestTransport(map[int][]int{1: {10, 20}, 2: {30}})
+	crawler := newSteamerTestCrawler(transport, NewFileStore(t.TempDir()), &CrawlerOptions{
+		Depth:       SteamerStageSearch,
+		PagesAll:    true,
+		SearchCount: 2,
+		SearchMode:  SteamerSearchInfinite})
+	if err := crawler.Run(context.Background()); err != nil {
+		t.Fatalf("Crawler.Run: %v", err)
+	}
+	if got := transport.Requests(SteamStoreHost + "/search/results/"); got != 2 {
+		t.Errorf("search results requests = %d, want 2", got)
+	}
+	if got := transport.Requests(SteamStoreHost + "/search/"); got != 2 {
+		t.Errorf("search requests = %d, want only the 2 JSON requests", got)
+	}
+	// the total count sets the last page, so -all ends after the second page of 2
+	if crawler.Log.TotalCount != 3 || crawler.Log.PagesTo != 2 {
+		t.Errorf("Log.TotalCount, Log.PagesTo = %d, %d, want 3, 2", crawler.Log.TotalCount, crawler.Log.PagesTo)
+	}
+	if got := len(crawler.SummaryCSV); got != 3 {
+		t.Errorf("len(SummaryCSV) = %d, want 3", got)
+	}
+}
@@ -0,0 +1,50 @@
+package steamer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type SteamerDeadLetter struct {
+	Failures  []SteamerFailure `json:"failures"`
+	PagesFrom int              `json:"pages_from"`
+	PagesTo   int              `json:"pages_to"`
+	RunID     string           `json:"run_id"`
+}
+
+func ReadSteamerDeadLetter(name string) (*SteamerDeadLetter, error) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	steamerDeadLetter := &SteamerDeadLetter{}
+	err = json.Unmarshal(b, steamerDeadLetter)
+	return steamerDeadLetter, err
+}
+
+func (steamerDeadLetter *SteamerDeadLetter) Tasks() []SteamerTask {
+	var steamerTasks []SteamerTask
+	for _, steamerFailure := range steamerDeadLetter.Failures {
+		steamerTasks = append(steamerTasks, steamerFailure.SteamerTask)
+	}
+	return steamerTasks
+}
+
+func writeSteamerDeadLetter(fullpath string, s *SteamerDeadLetter) error {
+	err := os.MkdirAll(fullpath, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	// named by run so a retry-failed run over the same pages never replaces the file it reads
+	filename := fmt.Sprintf("%s-deadletter.json", s.RunID)
+	fullname := filepath.Join(fullpath, filename)
+	err = ioutil.WriteFile(fullname, b, os.ModePerm)
+	return err
+}
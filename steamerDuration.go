@@ -0,0 +1,22 @@
+package steamer
+
+import "time"
+
+type SteamerDuration time.Duration
+
+func (steamerDuration SteamerDuration) MarshalText() ([]byte, error) {
+	return []byte(steamerDuration.String()), nil
+}
+
+func (steamerDuration SteamerDuration) String() string {
+	return time.Duration(steamerDuration).String()
+}
+
+func (steamerDuration *SteamerDuration) UnmarshalText(b []byte) error {
+	d, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*steamerDuration = SteamerDuration(d)
+	return nil
+}
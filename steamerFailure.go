@@ -0,0 +1,25 @@
+package steamer
+
+import "time"
+
+type SteamerFailure struct {
+	SteamerTask
+	Error      string    `json:"error"`
+	Status     string    `json:"status"`
+	StatusCode int       `json:"status_code"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+func NewSteamerFailure(task SteamerTask, snapshot *Snapshot, err error) SteamerFailure {
+	steamerFailure := SteamerFailure{
+		SteamerTask: task,
+		Timestamp:   time.Now()}
+	if err != nil {
+		steamerFailure.Error = err.Error()
+	}
+	if snapshot != nil {
+		steamerFailure.Status = snapshot.Status
+		steamerFailure.StatusCode = snapshot.StatusCode
+	}
+	return steamerFailure
+}
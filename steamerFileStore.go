@@ -0,0 +1,152 @@
+package steamer
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type FileStore struct {
+	Fullpath string
+
+	index     *SteamerVisitIndex
+	indexErr  error
+	indexOnce *sync.Once
+}
+
+func NewFileStore(fullpath string) *FileStore {
+	return &FileStore{
+		Fullpath:  fullpath,
+		indexOnce: &sync.Once{}}
+}
+
+func DefaultFileStorePath() (string, error) {
+	user, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(user.HomeDir, "Desktop", "steambot"), nil
+}
+
+func (fileStore *FileStore) Close() error {
+	if fileStore.index == nil {
+		return nil
+	}
+	return fileStore.index.Flush()
+}
+
+func (fileStore *FileStore) VisitIndex() (*SteamerVisitIndex, error) {
+	fileStore.indexOnce.Do(func() {
+		fileStore.indexErr = os.MkdirAll(fileStore.Fullpath, os.ModePerm)
+		if fileStore.indexErr != nil {
+			return
+		}
+		fileStore.index, fileStore.indexErr = NewSteamerVisitIndex(filepath.Join(fileStore.Fullpath, "visits.db"), func() (map[string]SteamerVisit, error) {
+			steamerVisits := map[string]SteamerVisit{}
+			for _, host := range []string{SteamStoreHost, SteamChartsHost} {
+				if err := readSnapshotVisits(filepath.Join(fileStore.Fullpath, host), steamerVisits); err != nil {
+					return steamerVisits, err
+				}
+			}
+			return steamerVisits, nil
+		})
+	})
+	return fileStore.index, fileStore.indexErr
+}
+
+func (fileStore *FileStore) VisitedURL(URL *url.URL) (time.Time, bool, error) {
+	steamerVisitIndex, err := fileStore.VisitIndex()
+	if err != nil {
+		return visitedURL(filepath.Join(fileStore.Fullpath, URL.Host), URL)
+	}
+	steamerVisit, ok := steamerVisitIndex.Visited(URL)
+	return steamerVisit.TimeEnd, ok, nil
+}
+
+func (fileStore *FileStore) WriteSnapshot(s *Snapshot) error {
+	if s.request == nil {
+		return errors.New("Snapshot.request empty")
+	}
+	err := writeSnapshotBody(filepath.Join(fileStore.Fullpath, "bodies"), s)
+	if err != nil {
+		return err
+	}
+	return writeSnapshot(filepath.Join(fileStore.Fullpath, s.request.URL.Host), s)
+}
+
+func (fileStore *FileStore) WriteVisit(s *Snapshot) error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return err
+	}
+	steamerVisitIndex, err := fileStore.VisitIndex()
+	if err != nil {
+		return err
+	}
+	return steamerVisitIndex.Visit(u, s.StatusCode, s.TimeEnd)
+}
+
+func (fileStore *FileStore) WriteSteamChartPage(s *SteamChartPage) error {
+	return writeSteamChartPage(filepath.Join(fileStore.Fullpath, "games", strconv.Itoa(s.AppID)), s)
+}
+
+func (fileStore *FileStore) WriteSteamGameAbbreviation(s *SteamGameAbbreviation) error {
+	return writeSteamGameAbbreviation(filepath.Join(fileStore.Fullpath, "games", strconv.Itoa(s.AppID)), s)
+}
+
+func (fileStore *FileStore) WriteSteamGamePage(s *SteamGamePage) error {
+	return writeSteamGamePage(filepath.Join(fileStore.Fullpath, "games", strconv.Itoa(s.AppID)), s)
+}
+
+func (fileStore *FileStore) WriteSteamGameSummary(s *SteamGameSummary) error {
+	return writeSteamGameSummary(filepath.Join(fileStore.Fullpath, "games", strconv.Itoa(s.AppID)), s)
+}
+
+func (fileStore *FileStore) WriteSteamSummaryCSV(name string, s *[]SteamSummaryCSV) error {
+	return writeSteamSummaryCSV(fileStore.Fullpath, name, s)
+}
+
+func (fileStore *FileStore) WriteSteamerDeadLetter(s *SteamerDeadLetter) error {
+	return writeSteamerDeadLetter(fileStore.Fullpath, s)
+}
+
+func (fileStore *FileStore) WriteSteamerLog(s *SteamerLog) error {
+	return writeSteamerLog(fileStore.Fullpath, s)
+}
+
+func (fileStore *FileStore) WriteSteamerSummary(s *SteamerSummary) error {
+	return writeSteamerSummary(fileStore.Fullpath, s)
+}
+
+func (fileStore *FileStore) ReadSnapshotBody(hash string) ([]byte, error) {
+	return readSnapshotBody(filepath.Join(fileStore.Fullpath, "bodies"), hash)
+}
+
+func (fileStore *FileStore) ReadSnapshotReplay(URL *url.URL) (SnapshotReplay, bool, error) {
+	return readSnapshotReplay(filepath.Join(fileStore.Fullpath, URL.Host), URL)
+}
+
+func (fileStore *FileStore) ReadSnapshotReplays() ([]SnapshotReplay, error) {
+	snapshotReplays := []SnapshotReplay{}
+	for _, host := range []string{SteamStoreHost, SteamChartsHost} {
+		s, err := readSnapshotReplays(filepath.Join(fileStore.Fullpath, host))
+		if err != nil {
+			return snapshotReplays, err
+		}
+		snapshotReplays = append(snapshotReplays, s...)
+	}
+	return snapshotReplays, nil
+}
+
+func (fileStore *FileStore) ReadSteamSearchFilterCatalog() (*SteamSearchFilterCatalog, error) {
+	return readSteamSearchFilterCatalog(fileStore.Fullpath)
+}
+
+func (fileStore *FileStore) WriteSteamSearchFilterCatalog(s *SteamSearchFilterCatalog) error {
+	return writeSteamSearchFilterCatalog(fileStore.Fullpath, s)
+}
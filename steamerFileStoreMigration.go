@@ -0,0 +1,191 @@
+package steamer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type FileStoreMigration struct {
+	Moved   int      `json:"moved"`
+	Skipped []string `json:"skipped"`
+}
+
+type fileStoreMigrationFile struct {
+	AppID *int   `json:"app_ID"`
+	URL   string `json:"URL"`
+}
+
+var fileStoreMigrationPrefixes = []string{
+	"chart-result-",
+	"page-result-",
+	"search-result-",
+	"summary-"}
+
+func (fileStore *FileStore) Migrate() (*FileStoreMigration, error) {
+	fileStoreMigration := &FileStoreMigration{
+		Skipped: []string{}}
+	err := fileStore.migrateGames(fileStoreMigration)
+	if err != nil {
+		return fileStoreMigration, err
+	}
+	for _, host := range []string{SteamChartsHost, SteamStoreHost} {
+		err = fileStore.migrateSnapshots(fileStoreMigration, filepath.Join(fileStore.Fullpath, host))
+		if err != nil {
+			return fileStoreMigration, err
+		}
+	}
+	return fileStoreMigration, nil
+}
+
+func (fileStore *FileStore) migrateGames(fileStoreMigration *FileStoreMigration) error {
+	fullpath := filepath.Join(fileStore.Fullpath, "games")
+	directories, err := ioutil.ReadDir(fullpath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	numeric := regexp.MustCompile(`^\d+$`)
+	for _, directory := range directories {
+		// an empty ASCII name wrote straight into games/ (e.g. page-result-.json), where every such app collided
+		if directory.IsDir() != true {
+			fullname := filepath.Join(fullpath, directory.Name())
+			err = fileStore.migrateGame(fileStoreMigration, fullname, readFileStoreMigrationAppID(fullname))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if numeric.MatchString(directory.Name()) {
+			continue
+		}
+		dirname := filepath.Join(fullpath, directory.Name())
+		files, err := ioutil.ReadDir(dirname)
+		if err != nil {
+			return err
+		}
+		appIDs := map[string]int{}
+		directoryAppIDs := map[int]bool{}
+		for _, file := range files {
+			appID := readFileStoreMigrationAppID(filepath.Join(dirname, file.Name()))
+			if appID > -1 {
+				appIDs[file.Name()] = appID
+				directoryAppIDs[appID] = true
+			}
+		}
+		for _, file := range files {
+			appID, ok := appIDs[file.Name()]
+			// files without an identity (older chart results) inherit the directory's only AppID
+			if ok != true && len(directoryAppIDs) == 1 {
+				for ID := range directoryAppIDs {
+					appID, ok = ID, true
+				}
+			}
+			if ok != true {
+				appID = -1
+			}
+			err = fileStore.migrateGame(fileStoreMigration, filepath.Join(dirname, file.Name()), appID)
+			if err != nil {
+				return err
+			}
+		}
+		os.Remove(dirname)
+	}
+	return nil
+}
+
+func (fileStore *FileStore) migrateGame(fileStoreMigration *FileStoreMigration, fullname string, appID int) error {
+	prefix := fileStoreMigrationPrefix(filepath.Base(fullname))
+	if appID < 0 || len(prefix) == 0 {
+		fileStoreMigration.Skipped = append(fileStoreMigration.Skipped, fullname)
+		return nil
+	}
+	migrated := filepath.Join(fileStore.Fullpath, "games", strconv.Itoa(appID))
+	err := os.MkdirAll(migrated, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	migratedname := filepath.Join(migrated, fmt.Sprintf("%s%d.json", prefix, appID))
+	if _, err := os.Stat(migratedname); err == nil {
+		fileStoreMigration.Skipped = append(fileStoreMigration.Skipped, fullname)
+		return nil
+	}
+	err = os.Rename(fullname, migratedname)
+	if err != nil {
+		return err
+	}
+	fileStoreMigration.Moved = fileStoreMigration.Moved + 1
+	return nil
+}
+
+func (fileStore *FileStore) migrateSnapshots(fileStoreMigration *FileStoreMigration, fullpath string) error {
+	files, err := ioutil.ReadDir(fullpath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), "app-") {
+			continue
+		}
+		fullname := filepath.Join(fullpath, file.Name())
+		b, err := ioutil.ReadFile(fullname)
+		if err != nil {
+			return err
+		}
+		var fileStoreMigrationFile fileStoreMigrationFile
+		if err := json.Unmarshal(b, &fileStoreMigrationFile); err != nil {
+			fileStoreMigration.Skipped = append(fileStoreMigration.Skipped, fullname)
+			continue
+		}
+		u, err := url.Parse(fileStoreMigrationFile.URL)
+		if err != nil || parseSteamAppID(u.Path) < 0 {
+			continue
+		}
+		migratedname := filepath.Join(fullpath, snapshotFilename(u))
+		if _, err := os.Stat(migratedname); err == nil {
+			fileStoreMigration.Skipped = append(fileStoreMigration.Skipped, fullname)
+			continue
+		}
+		err = os.Rename(fullname, migratedname)
+		if err != nil {
+			return err
+		}
+		fileStoreMigration.Moved = fileStoreMigration.Moved + 1
+	}
+	return nil
+}
+
+func fileStoreMigrationPrefix(filename string) string {
+	for _, prefix := range fileStoreMigrationPrefixes {
+		if strings.HasPrefix(filename, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+func readFileStoreMigrationAppID(fullname string) int {
+	b, err := ioutil.ReadFile(fullname)
+	if err != nil {
+		return -1
+	}
+	var fileStoreMigrationFile fileStoreMigrationFile
+	if err := json.Unmarshal(b, &fileStoreMigrationFile); err != nil {
+		return -1
+	}
+	if fileStoreMigrationFile.AppID != nil && *fileStoreMigrationFile.AppID > -1 {
+		return *fileStoreMigrationFile.AppID
+	}
+	return parseSteamAppID(fileStoreMigrationFile.URL)
+}
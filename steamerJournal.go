@@ -0,0 +1,118 @@
+package steamer
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type SteamerJournal struct {
+	Name string
+
+	encoder *json.Encoder
+	file    *os.File
+	mu      *sync.Mutex
+}
+
+func NewSteamerJournal(name string) (*SteamerJournal, error) {
+	err := os.MkdirAll(filepath.Dir(name), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	return &SteamerJournal{
+		Name:    name,
+		encoder: json.NewEncoder(file),
+		file:    file,
+		mu:      &sync.Mutex{}}, nil
+}
+
+func ReadSteamerJournal(name string) ([]SteamerJournalEntry, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var steamerJournalEntries []SteamerJournalEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		steamerJournalEntry := SteamerJournalEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &steamerJournalEntry); err != nil {
+			// a crash can leave the last line half written
+			continue
+		}
+		steamerJournalEntries = append(steamerJournalEntries, steamerJournalEntry)
+	}
+	return steamerJournalEntries, scanner.Err()
+}
+
+func PendingSteamerJournal(steamerJournalEntries []SteamerJournalEntry) []SteamerJournalEntry {
+	var keys []string
+	last := map[string]SteamerJournalEntry{}
+	for _, steamerJournalEntry := range steamerJournalEntries {
+		key := steamerJournalEntry.Key()
+		previous, ok := last[key]
+		if ok != true {
+			keys = append(keys, key)
+		}
+		if steamerJournalEntry.Game == nil && ok {
+			steamerJournalEntry.Game = previous.Game
+		}
+		last[key] = steamerJournalEntry
+	}
+	var pending []SteamerJournalEntry
+	for _, key := range keys {
+		if isSteamerJournalPending(last[key]) {
+			pending = append(pending, last[key])
+		}
+	}
+	return pending
+}
+
+func isSteamerJournalPending(steamerJournalEntry SteamerJournalEntry) bool {
+	return steamerJournalEntry.Status != SteamerJournalDone && steamerJournalEntry.Status != SteamerJournalSkipped
+}
+
+func (steamerJournal *SteamerJournal) Close() error {
+	if steamerJournal == nil {
+		return nil
+	}
+	return steamerJournal.file.Close()
+}
+
+func (steamerJournal *SteamerJournal) Done(task SteamerTask) error {
+	task.Game = nil
+	return steamerJournal.write(NewSteamerJournalEntry(task, SteamerJournalDone, nil))
+}
+
+func (steamerJournal *SteamerJournal) Failed(task SteamerTask, err error) error {
+	task.Game = nil
+	return steamerJournal.write(NewSteamerJournalEntry(task, SteamerJournalFailed, err))
+}
+
+func (steamerJournal *SteamerJournal) Queue(task SteamerTask) error {
+	return steamerJournal.write(NewSteamerJournalEntry(task, SteamerJournalQueued, nil))
+}
+
+func (steamerJournal *SteamerJournal) Skipped(task SteamerTask) error {
+	task.Game = nil
+	return steamerJournal.write(NewSteamerJournalEntry(task, SteamerJournalSkipped, nil))
+}
+
+func (steamerJournal *SteamerJournal) write(steamerJournalEntry SteamerJournalEntry) error {
+	if steamerJournal == nil {
+		return nil
+	}
+	steamerJournal.mu.Lock()
+	defer steamerJournal.mu.Unlock()
+	return steamerJournal.encoder.Encode(steamerJournalEntry)
+}
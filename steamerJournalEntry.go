@@ -0,0 +1,28 @@
+package steamer
+
+import "time"
+
+const (
+	SteamerJournalDone    string = "done"
+	SteamerJournalFailed  string = "failed"
+	SteamerJournalQueued  string = "queued"
+	SteamerJournalSkipped string = "skipped"
+)
+
+type SteamerJournalEntry struct {
+	SteamerTask
+	Error     string    `json:"error,omitempty"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func NewSteamerJournalEntry(task SteamerTask, status string, err error) SteamerJournalEntry {
+	steamerJournalEntry := SteamerJournalEntry{
+		SteamerTask: task,
+		Status:      status,
+		Timestamp:   time.Now()}
+	if err != nil {
+		steamerJournalEntry.Error = err.Error()
+	}
+	return steamerJournalEntry
+}
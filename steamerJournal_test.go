@@ -0,0 +1,98 @@
+package steamer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPendingSteamerJournal(t *testing.T) {
+	game := &SteamGamePage{AppID: 10}
+	tests := []struct {
+		name   string
+		write  func(steamerJournal *SteamerJournal, task SteamerTask)
+		status string
+		game   bool
+	}{
+		{"queued", func(steamerJournal *SteamerJournal, task SteamerTask) {
+			steamerJournal.Queue(task)
+		}, SteamerJournalQueued, true},
+		{"done", func(steamerJournal *SteamerJournal, task SteamerTask) {
+			steamerJournal.Queue(task)
+			steamerJournal.Done(task)
+		}, "", false},
+		{"failed", func(steamerJournal *SteamerJournal, task SteamerTask) {
+			steamerJournal.Queue(task)
+			steamerJournal.Failed(task, errors.New("503 Service Unavailable"))
+		}, SteamerJournalFailed, true},
+		{"failed then done", func(steamerJournal *SteamerJournal, task SteamerTask) {
+			steamerJournal.Queue(task)
+			steamerJournal.Failed(task, errors.New("503 Service Unavailable"))
+			steamerJournal.Done(task)
+		}, "", false},
+		{"skipped", func(steamerJournal *SteamerJournal, task SteamerTask) {
+			steamerJournal.Queue(task)
+			steamerJournal.Skipped(task)
+		}, "", false},
+		{"done under another URL form", func(steamerJournal *SteamerJournal, task SteamerTask) {
+			steamerJournal.Queue(task)
+			task.URL = task.URL + "CounterStrike/?snr=1_7"
+			steamerJournal.Done(task)
+		}, "", false},
+	}
+	for _, test := range tests {
+		name := filepath.Join(t.TempDir(), "journal.jsonl")
+		steamerJournal, err := NewSteamerJournal(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.write(steamerJournal, NewSteamerTask(SteamerStageGame, "https://store.steampowered.com/app/10/", 1, game))
+		steamerJournal.Close()
+		steamerJournalEntries, err := ReadSteamerJournal(name)
+		if err != nil {
+			t.Fatalf("%s: ReadSteamerJournal: %v", test.name, err)
+		}
+		pending := PendingSteamerJournal(steamerJournalEntries)
+		if len(test.status) == 0 {
+			if len(pending) != 0 {
+				t.Errorf("%s: pending = %d entries, want 0", test.name, len(pending))
+			}
+			continue
+		}
+		if len(pending) != 1 {
+			t.Errorf("%s: pending = %d entries, want 1", test.name, len(pending))
+			continue
+		}
+		if pending[0].Status != test.status {
+			t.Errorf("%s: status = %q, want %q", test.name, pending[0].Status, test.status)
+		}
+		if (pending[0].Game != nil) != test.game {
+			t.Errorf("%s: game kept = %t, want %t", test.name, pending[0].Game != nil, test.game)
+		}
+	}
+}
+
+func TestReadSteamerJournalPartialLine(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "journal.jsonl")
+	steamerJournal, err := NewSteamerJournal(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steamerJournal.Queue(NewSteamerTask(SteamerStageSearch, "https://store.steampowered.com/search/?page=1", 1, nil))
+	steamerJournal.Queue(NewSteamerTask(SteamerStageSearch, "https://store.steampowered.com/search/?page=2", 2, nil))
+	steamerJournal.Close()
+	file, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"page":1,"stage":"search","URL":"https://store.steampowered.com/search/?page=1","status":"do`)
+	file.Close()
+	steamerJournalEntries, err := ReadSteamerJournal(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending := PendingSteamerJournal(steamerJournalEntries); len(pending) != 2 {
+		t.Errorf("pending = %d entries, want 2", len(pending))
+	}
+}
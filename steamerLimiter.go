@@ -0,0 +1,167 @@
+package steamer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	SteamChartsHost string = "steamcharts.com"
+	SteamStoreHost  string = "store.steampowered.com"
+)
+
+type SteamerHostLimit struct {
+	Concurrency       int     `json:"concurrency" toml:"concurrency" yaml:"concurrency"`
+	RequestsPerSecond float64 `json:"requests_per_second" toml:"requests_per_second" yaml:"requests_per_second"`
+}
+
+type SteamerLimiter struct {
+	Transport http.RoundTripper
+
+	hosts map[string]*steamerHostLimiter
+	mu    *sync.RWMutex
+}
+
+type steamerHostLimiter struct {
+	interval time.Duration
+	mu       *sync.Mutex
+	next     time.Time
+	queue    int64
+	sem      chan struct{}
+}
+
+type steamerLimiterBody struct {
+	io.ReadCloser
+	once    *sync.Once
+	release func()
+}
+
+func NewSteamerLimiter(transport http.RoundTripper, limits map[string]SteamerHostLimit) *SteamerLimiter {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	steamerLimiter := &SteamerLimiter{
+		Transport: transport,
+		mu:        &sync.RWMutex{}}
+	steamerLimiter.SetLimits(limits)
+	return steamerLimiter
+}
+
+func (steamerLimiter *SteamerLimiter) SetLimits(limits map[string]SteamerHostLimit) {
+	hosts := map[string]*steamerHostLimiter{}
+	for host, limit := range limits {
+		steamerHostLimiter := &steamerHostLimiter{
+			mu: &sync.Mutex{}}
+		if limit.Concurrency > 0 {
+			steamerHostLimiter.sem = make(chan struct{}, limit.Concurrency)
+		}
+		if limit.RequestsPerSecond > 0 {
+			steamerHostLimiter.interval = time.Duration(float64(time.Second) / limit.RequestsPerSecond)
+		}
+		hosts[host] = steamerHostLimiter
+	}
+	// requests already holding a slot release it on the limiter they were admitted by
+	steamerLimiter.mu.Lock()
+	defer steamerLimiter.mu.Unlock()
+	steamerLimiter.hosts = hosts
+}
+
+func (steamerLimiter *SteamerLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	h, ok := steamerLimiter.host(req.URL.Hostname())
+	if ok != true {
+		return steamerLimiter.Transport.RoundTrip(req)
+	}
+	atomic.AddInt64(&h.queue, 1)
+	release, err := h.acquire(req)
+	atomic.AddInt64(&h.queue, -1)
+	if err != nil {
+		return nil, err
+	}
+	// requests that made it through the queue are left to finish on shutdown
+	ctx, cancel := context.WithoutCancel(req.Context()), context.CancelFunc(func() {})
+	if deadline, ok := req.Context().Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	res, err := steamerLimiter.Transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		release()
+		return res, err
+	}
+	res.Body = &steamerLimiterBody{
+		ReadCloser: res.Body,
+		once:       &sync.Once{},
+		release: func() {
+			cancel()
+			release()
+		}}
+	return res, err
+}
+
+func (steamerLimiter *SteamerLimiter) Active(host string) int {
+	h, ok := steamerLimiter.host(host)
+	if ok != true {
+		return 0
+	}
+	return len(h.sem)
+}
+
+func (steamerLimiter *SteamerLimiter) Queue(host string) int {
+	h, ok := steamerLimiter.host(host)
+	if ok != true {
+		return 0
+	}
+	return int(atomic.LoadInt64(&h.queue))
+}
+
+func (steamerLimiter *SteamerLimiter) host(host string) (*steamerHostLimiter, bool) {
+	steamerLimiter.mu.RLock()
+	defer steamerLimiter.mu.RUnlock()
+	h, ok := steamerLimiter.hosts[host]
+	return h, ok
+}
+
+func (h *steamerHostLimiter) acquire(req *http.Request) (func(), error) {
+	release := func() {}
+	if h.sem != nil {
+		select {
+		case h.sem <- struct{}{}:
+			release = func() { <-h.sem }
+		case <-req.Context().Done():
+			return release, req.Context().Err()
+		}
+	}
+	if h.interval == 0 {
+		return release, nil
+	}
+	h.mu.Lock()
+	now := time.Now()
+	if h.next.Before(now) {
+		h.next = now
+	}
+	wait := h.next.Sub(now)
+	h.next = h.next.Add(h.interval)
+	h.mu.Unlock()
+	if wait == 0 {
+		return release, nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return release, nil
+	case <-req.Context().Done():
+		release()
+		return func() {}, req.Context().Err()
+	}
+}
+
+func (steamerLimiterBody *steamerLimiterBody) Close() error {
+	err := steamerLimiterBody.ReadCloser.Close()
+	steamerLimiterBody.once.Do(steamerLimiterBody.release)
+	return err
+}
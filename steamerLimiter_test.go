@@ -0,0 +1,92 @@
+package steamer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type steamerLimiterTestTransport struct {
+	active int64
+	max    int64
+	wait   time.Duration
+}
+
+func (steamerLimiterTestTransport *steamerLimiterTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	active := atomic.AddInt64(&steamerLimiterTestTransport.active, 1)
+	for {
+		max := atomic.LoadInt64(&steamerLimiterTestTransport.max)
+		if active <= max || atomic.CompareAndSwapInt64(&steamerLimiterTestTransport.max, max, active) {
+			break
+		}
+	}
+	time.Sleep(steamerLimiterTestTransport.wait)
+	atomic.AddInt64(&steamerLimiterTestTransport.active, -1)
+	recorder := httptest.NewRecorder()
+	res := recorder.Result()
+	res.Request = req
+	return res, nil
+}
+
+func TestSteamerLimiterConcurrency(t *testing.T) {
+	transport := &steamerLimiterTestTransport{wait: 20 * time.Millisecond}
+	steamerLimiter := NewSteamerLimiter(transport, map[string]SteamerHostLimit{
+		SteamStoreHost: {Concurrency: 2}})
+	client := &http.Client{Transport: steamerLimiter}
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := client.Get("https://" + SteamStoreHost + "/")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ioutil.ReadAll(res.Body)
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt64(&transport.max); got != 2 {
+		t.Errorf("max concurrent requests = %d, want 2", got)
+	}
+	if got := steamerLimiter.Active(SteamStoreHost); got != 0 {
+		t.Errorf("SteamerLimiter.Active = %d after every body was closed, want 0", got)
+	}
+}
+
+func TestSteamerLimiterRequestsPerSecond(t *testing.T) {
+	steamerLimiter := NewSteamerLimiter(&steamerLimiterTestTransport{}, map[string]SteamerHostLimit{
+		SteamChartsHost: {RequestsPerSecond: 20}})
+	client := &http.Client{Transport: steamerLimiter}
+	timeStart := time.Now()
+	for i := 0; i < 5; i++ {
+		res, err := client.Get("https://" + SteamChartsHost + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+	// the first request goes straight through and each of the other four waits 50ms
+	if got := time.Since(timeStart); got < 200*time.Millisecond {
+		t.Errorf("5 requests at 20/s took %s, want at least 200ms", got)
+	}
+	// hosts without a limit are not held back
+	steamerLimiter.SetLimits(nil)
+	timeStart = time.Now()
+	for i := 0; i < 5; i++ {
+		res, err := client.Get("https://" + SteamChartsHost + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+	if got := time.Since(timeStart); got >= 200*time.Millisecond {
+		t.Errorf("5 unlimited requests took %s, want less than 200ms", got)
+	}
+}
@@ -1,23 +1,35 @@
-package main
+package steamer
 
 import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/user"
 	"path/filepath"
 	"time"
 )
 
 type SteamerLog struct {
+	Config        *SteamerConfig    `json:"config"`
+	Depth         string            `json:"depth"`
+	Failures      int               `json:"failures"`
+	Incomplete    bool              `json:"incomplete"`
 	PagesFrom     int               `json:"pages_from"`
+	PagesLast     int               `json:"pages_last"`
 	PagesTo       int               `json:"pages_to"`
 	PagesOK       *SteamerLogPageOK `json:"pages_ok"`
+	RunID         string            `json:"run_ID"`
+	Stored        int               `json:"stored"`
 	TerminateZero bool              `json:"terminate_zero"`
 	TimeDuration  time.Duration     `json:"time_duration"`
 	TimeEnd       time.Time         `json:"time_end"`
 	TimeStart     time.Time         `json:"time_start"`
+	TotalCount    int               `json:"total_count"`
+	WARCFailures  int               `json:"warc_failures"`
+}
+
+func NewSteamerRunID() string {
+	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), os.Getpid())
 }
 
 func writeSteamerLog(fullpath string, s *SteamerLog) error {
@@ -34,13 +46,3 @@ func writeSteamerLog(fullpath string, s *SteamerLog) error {
 	err = ioutil.WriteFile(fullname, b, os.ModePerm)
 	return err
 }
-
-func writeSteamerLogDefault(s *SteamerLog) error {
-	user, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-	fullpath := filepath.Join(user.HomeDir, "Desktop", "steambot")
-	err = writeSteamerLog(fullpath, s)
-	return err
-}
@@ -0,0 +1,11 @@
+package steamer
+
+type SteamerLogPage struct {
+	ChartsFailed int  `json:"charts_failed"`
+	ChartsOK     int  `json:"charts_ok"`
+	GamesFetched int  `json:"games_fetched"`
+	GamesFound   int  `json:"games_found"`
+	GamesSkipped int  `json:"games_skipped"`
+	OK           bool `json:"ok"`
+	StatusCode   int  `json:"status_code"`
+}
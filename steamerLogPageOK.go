@@ -1,21 +1,97 @@
-package main
+package steamer
 
-type SteamerLogPageOK map[int]bool
+import (
+	"encoding/json"
+	"sync"
+)
+
+type SteamerLogPageOK struct {
+	mu    sync.Mutex
+	pages map[int]*SteamerLogPage
+}
+
+func NewSteamerLogPageOK() *SteamerLogPageOK {
+	return &SteamerLogPageOK{
+		pages: map[int]*SteamerLogPage{}}
+}
 
 func (steamerLogPageOK *SteamerLogPageOK) Add(page int, status bool) bool {
-	ok := steamerLogPageOK.Has(page)
-	if ok != true {
-		(*steamerLogPageOK)[page] = status
-	}
+	steamerLogPageOK.mu.Lock()
+	defer steamerLogPageOK.mu.Unlock()
+	_, ok := steamerLogPageOK.pages[page]
+	steamerLogPageOK.page(page).OK = status
 	return (ok == false)
 }
 
-func (steamerLogPageOK *SteamerLogPageOK) Get(page int) (bool, bool) {
-	pageStatus, ok := (*steamerLogPageOK)[page]
-	return pageStatus, ok
+func (steamerLogPageOK *SteamerLogPageOK) AddChart(page int, status bool) {
+	steamerLogPageOK.mu.Lock()
+	defer steamerLogPageOK.mu.Unlock()
+	if status {
+		steamerLogPageOK.page(page).ChartsOK++
+	} else {
+		steamerLogPageOK.page(page).ChartsFailed++
+	}
+}
+
+func (steamerLogPageOK *SteamerLogPageOK) AddGamesFetched(page int) {
+	steamerLogPageOK.mu.Lock()
+	defer steamerLogPageOK.mu.Unlock()
+	steamerLogPageOK.page(page).GamesFetched++
+}
+
+func (steamerLogPageOK *SteamerLogPageOK) AddGamesFound(page int, n int) {
+	steamerLogPageOK.mu.Lock()
+	defer steamerLogPageOK.mu.Unlock()
+	steamerLogPageOK.page(page).GamesFound += n
+}
+
+func (steamerLogPageOK *SteamerLogPageOK) AddGamesSkipped(page int, n int) {
+	steamerLogPageOK.mu.Lock()
+	defer steamerLogPageOK.mu.Unlock()
+	steamerLogPageOK.page(page).GamesSkipped += n
+}
+
+func (steamerLogPageOK *SteamerLogPageOK) AddStatusCode(page int, statusCode int) {
+	steamerLogPageOK.mu.Lock()
+	defer steamerLogPageOK.mu.Unlock()
+	steamerLogPageOK.page(page).StatusCode = statusCode
+}
+
+func (steamerLogPageOK *SteamerLogPageOK) Get(page int) (SteamerLogPage, bool) {
+	steamerLogPageOK.mu.Lock()
+	defer steamerLogPageOK.mu.Unlock()
+	steamerLogPage, ok := steamerLogPageOK.pages[page]
+	if ok != true {
+		return SteamerLogPage{}, ok
+	}
+	return *steamerLogPage, ok
 }
 
 func (steamerLogPageOK *SteamerLogPageOK) Has(page int) bool {
 	_, ok := steamerLogPageOK.Get(page)
 	return ok
 }
+
+func (steamerLogPageOK *SteamerLogPageOK) MarshalJSON() ([]byte, error) {
+	steamerLogPageOK.mu.Lock()
+	defer steamerLogPageOK.mu.Unlock()
+	return json.Marshal(steamerLogPageOK.pages)
+}
+
+func (steamerLogPageOK *SteamerLogPageOK) UnmarshalJSON(b []byte) error {
+	steamerLogPageOK.mu.Lock()
+	defer steamerLogPageOK.mu.Unlock()
+	return json.Unmarshal(b, &steamerLogPageOK.pages)
+}
+
+func (steamerLogPageOK *SteamerLogPageOK) page(page int) *SteamerLogPage {
+	if steamerLogPageOK.pages == nil {
+		steamerLogPageOK.pages = map[int]*SteamerLogPage{}
+	}
+	steamerLogPage, ok := steamerLogPageOK.pages[page]
+	if ok != true {
+		steamerLogPage = &SteamerLogPage{}
+		steamerLogPageOK.pages[page] = steamerLogPage
+	}
+	return steamerLogPage
+}
@@ -0,0 +1,132 @@
+package steamer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type SteamerReparse struct {
+	Abbreviations        int      `json:"abbreviations"`
+	AbbreviationsSkipped int      `json:"abbreviations_skipped"`
+	Charts               int      `json:"charts"`
+	Failed               []string `json:"failed"`
+	Games                int      `json:"games"`
+	Skipped              []string `json:"skipped"`
+}
+
+func Reparse(replayStore ReplayStore, store Store) (*SteamerReparse, error) {
+	steamerReparse := &SteamerReparse{
+		Failed:  []string{},
+		Skipped: []string{}}
+	snapshotReplays, err := replayStore.ReadSnapshotReplays()
+	if err != nil {
+		return steamerReparse, err
+	}
+	latest := map[string]SnapshotReplay{}
+	for _, snapshotReplay := range snapshotReplays {
+		if snapshotReplay.StatusCode != http.StatusOK {
+			continue
+		}
+		if x, ok := latest[snapshotReplay.URL]; ok && x.TimeEnd.After(snapshotReplay.TimeEnd) {
+			continue
+		}
+		latest[snapshotReplay.URL] = snapshotReplay
+	}
+	URLs := []string{}
+	for URL := range latest {
+		URLs = append(URLs, URL)
+	}
+	sort.Strings(URLs)
+	for _, URL := range URLs {
+		snapshotReplay := latest[URL]
+		body, err := replayStore.ReadSnapshotBody(snapshotReplay.BodyHash)
+		if err == nil {
+			err = steamerReparse.reparse(store, snapshotReplay, body)
+		}
+		if err != nil {
+			steamerReparse.Skipped = append(steamerReparse.Skipped, fmt.Sprintf("%s (%s)", URL, err))
+		}
+	}
+	if len(steamerReparse.Failed) > 0 {
+		return steamerReparse, fmt.Errorf("SteamerReparse %d store writes failed", len(steamerReparse.Failed))
+	}
+	return steamerReparse, nil
+}
+
+func (steamerReparse *SteamerReparse) fail(name string, err error) {
+	steamerReparse.Failed = append(steamerReparse.Failed, fmt.Sprintf("%s (%s)", name, err))
+}
+
+func (steamerReparse *SteamerReparse) reparse(store Store, snapshotReplay SnapshotReplay, body []byte) error {
+	u, err := url.Parse(snapshotReplay.URL)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(u.Path, "/search/results") {
+		steamSearchResults, err := NewSteamSearchResults(body)
+		if err != nil {
+			return err
+		}
+		goQuerySelection, err := steamSearchResults.Selection()
+		if err != nil {
+			return err
+		}
+		return steamerReparse.reparseSteamGameAbbreviations(store, snapshotReplay, goQuerySelection)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	switch {
+	case strings.HasPrefix(u.Path, "/search"):
+		return steamerReparse.reparseSteamGameAbbreviations(store, snapshotReplay, doc.Find("a.search_result_row[href]"))
+	case u.Host == SteamChartsHost && parseSteamAppID(u.Path) > -1:
+		steamChartPage, err := newSteamChartPageFromDocument(snapshotReplay.URL, doc)
+		if err != nil {
+			return err
+		}
+		steamChartPage.Timestamp = snapshotReplay.TimeEnd
+		if err := store.WriteSteamChartPage(steamChartPage); err != nil {
+			steamerReparse.fail(snapshotReplay.URL, err)
+			return nil
+		}
+		steamerReparse.Charts = steamerReparse.Charts + 1
+	case u.Host == SteamStoreHost && parseSteamAppID(u.Path) > -1:
+		steamGamePage, err := newSteamGamePageFromDocument(snapshotReplay.URL, doc)
+		if err != nil {
+			return err
+		}
+		steamGamePage.Timestamp = snapshotReplay.TimeEnd
+		if err := store.WriteSteamGamePage(steamGamePage); err != nil {
+			steamerReparse.fail(snapshotReplay.URL, err)
+			return nil
+		}
+		steamerReparse.Games = steamerReparse.Games + 1
+	default:
+		return errors.New("SnapshotReplay.URL has no parser")
+	}
+	return nil
+}
+
+func (steamerReparse *SteamerReparse) reparseSteamGameAbbreviations(store Store, snapshotReplay SnapshotReplay, goQuerySelection *goquery.Selection) error {
+	eachSteamGameAbbreviation(goQuerySelection,
+		func(s *SteamGameAbbreviation) {
+			s.Timestamp = snapshotReplay.TimeEnd
+			if err := store.WriteSteamGameAbbreviation(s); err != nil {
+				steamerReparse.fail(fmt.Sprintf("%s app %d", snapshotReplay.URL, s.AppID), err)
+				return
+			}
+			steamerReparse.Abbreviations = steamerReparse.Abbreviations + 1
+		},
+		func(s *SteamGameAbbreviation) {
+			steamerReparse.AbbreviationsSkipped = steamerReparse.AbbreviationsSkipped + 1
+		})
+	return nil
+}
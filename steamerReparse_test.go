@@ -0,0 +1,48 @@
+package steamer
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// steamerReparseTestStore fails every game page write
+type steamerReparseTestStore struct {
+	Store
+}
+
+func (steamerReparseTestStore *steamerReparseTestStore) WriteSteamGamePage(s *SteamGamePage) error {
+	return errors.New("disk full")
+}
+
+func TestReparse(t *testing.T) {
+	fileStore := NewFileStore(t.TempDir())
+	transport := newSteamerTestTransport(map[int][]int{1: {10, 20}})
+	crawler := newSteamerTestCrawler(transport, fileStore, &CrawlerOptions{
+		Write: &SteamerWrite{Snapshot: true}})
+	if err := crawler.Run(context.Background()); err != nil {
+		t.Fatalf("Crawler.Run: %v", err)
+	}
+	requests := transport.Requests("")
+	steamerReparse, err := Reparse(fileStore, NewFileStore(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Reparse: %v", err)
+	}
+	if got := transport.Requests(""); got != requests {
+		t.Errorf("Reparse made %d requests, want 0", got-requests)
+	}
+	if steamerReparse.Abbreviations != 2 || steamerReparse.Games != 2 || steamerReparse.Charts != 2 {
+		t.Errorf("SteamerReparse abbreviations, games, charts = %d, %d, %d, want 2, 2, 2", steamerReparse.Abbreviations, steamerReparse.Games, steamerReparse.Charts)
+	}
+	if len(steamerReparse.Failed) != 0 || len(steamerReparse.Skipped) != 0 {
+		t.Errorf("SteamerReparse failed, skipped = %v, %v, want none", steamerReparse.Failed, steamerReparse.Skipped)
+	}
+	// failed writes are reported and not counted
+	steamerReparse, err = Reparse(fileStore, &steamerReparseTestStore{NewFileStore(t.TempDir())})
+	if err == nil {
+		t.Error("Reparse = nil with failing game writes, want an error")
+	}
+	if steamerReparse.Games != 0 || len(steamerReparse.Failed) != 2 {
+		t.Errorf("SteamerReparse games, failed = %d, %d, want 0, 2", steamerReparse.Games, len(steamerReparse.Failed))
+	}
+}
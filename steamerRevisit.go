@@ -0,0 +1,52 @@
+package steamer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type SteamerRevisit struct {
+	Chart       bool            `json:"chart" toml:"chart" yaml:"chart"`
+	ChartAfter  SteamerDuration `json:"chart_after" toml:"chart_after" yaml:"chart_after"`
+	Game        bool            `json:"game" toml:"game" yaml:"game"`
+	GameAfter   SteamerDuration `json:"game_after" toml:"game_after" yaml:"game_after"`
+	Search      bool            `json:"search" toml:"search" yaml:"search"`
+	SearchAfter SteamerDuration `json:"search_after" toml:"search_after" yaml:"search_after"`
+}
+
+func NewSteamerRevisit(level int) *SteamerRevisit {
+	return &SteamerRevisit{
+		Chart:  level > 2,
+		Game:   level > 1,
+		Search: level > 0}
+}
+
+func (steamerRevisit *SteamerRevisit) String() string {
+	var revisit []string
+	for _, x := range []struct {
+		always bool
+		after  SteamerDuration
+		name   string
+	}{
+		{steamerRevisit.Search, steamerRevisit.SearchAfter, "PAGES"},
+		{steamerRevisit.Game, steamerRevisit.GameAfter, "GAMES"},
+		{steamerRevisit.Chart, steamerRevisit.ChartAfter, "CHARTS"}} {
+		if x.always {
+			revisit = append(revisit, x.name)
+		} else if x.after > 0 {
+			revisit = append(revisit, fmt.Sprintf("%s > %s", x.name, x.after))
+		}
+	}
+	if len(revisit) == 0 {
+		return "NONE"
+	}
+	return strings.Join(revisit, " + ")
+}
+
+func (steamerRevisit *SteamerRevisit) Validate() error {
+	if steamerRevisit.ChartAfter < 0 || steamerRevisit.GameAfter < 0 || steamerRevisit.SearchAfter < 0 {
+		return errors.New("SteamerRevisit after must not be negative")
+	}
+	return nil
+}
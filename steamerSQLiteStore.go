@@ -0,0 +1,567 @@
+package steamer
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const steamerSQLiteSchema string = `
+CREATE TABLE IF NOT EXISTS runs (
+	run_id         TEXT PRIMARY KEY,
+	config         TEXT,
+	depth          TEXT,
+	pages_from     INTEGER,
+	pages_to       INTEGER,
+	pages_ok       TEXT,
+	failures       INTEGER,
+	incomplete     INTEGER,
+	terminate_zero INTEGER,
+	time_start     DATETIME,
+	time_end       DATETIME,
+	time_duration  INTEGER,
+	total_count    INTEGER,
+	warc_failures  INTEGER,
+	stored         INTEGER
+);
+CREATE TABLE IF NOT EXISTS run_summaries (
+	run_id  TEXT PRIMARY KEY,
+	games   INTEGER,
+	summary TEXT
+);
+CREATE TABLE IF NOT EXISTS run_summary_csv (
+	run_id TEXT,
+	name   TEXT,
+	csv    TEXT,
+	PRIMARY KEY (run_id, name)
+);
+CREATE TABLE IF NOT EXISTS failures (
+	run_id      TEXT,
+	stage       TEXT,
+	page        INTEGER,
+	url         TEXT,
+	status      TEXT,
+	status_code INTEGER,
+	error       TEXT,
+	timestamp   DATETIME
+);
+CREATE TABLE IF NOT EXISTS snapshots (
+	run_id        TEXT,
+	app_id        INTEGER,
+	url           TEXT,
+	host          TEXT,
+	method        TEXT,
+	status        TEXT,
+	status_code   INTEGER,
+	request_ok    INTEGER,
+	response_ok   INTEGER,
+	attempts      INTEGER,
+	time_start    DATETIME,
+	time_end      DATETIME,
+	time_duration INTEGER,
+	body_hash     TEXT,
+	cache         TEXT,
+	url_key       TEXT
+);
+CREATE INDEX IF NOT EXISTS snapshots_url ON snapshots (url);
+CREATE INDEX IF NOT EXISTS snapshots_app_id ON snapshots (host, app_id);
+CREATE INDEX IF NOT EXISTS snapshots_url_key ON snapshots (url_key);
+CREATE TABLE IF NOT EXISTS snapshot_bodies (
+	hash TEXT PRIMARY KEY,
+	body BLOB
+);
+CREATE TABLE IF NOT EXISTS visits (
+	url_key     TEXT PRIMARY KEY,
+	status_code INTEGER,
+	time_end    DATETIME
+);
+CREATE TABLE IF NOT EXISTS search_results (
+	app_id              INTEGER,
+	run_id              TEXT,
+	bundle_id           INTEGER,
+	package_id          INTEGER,
+	name                TEXT,
+	title               TEXT,
+	tag_ids             TEXT,
+	url                 TEXT,
+	platforms           TEXT,
+	price_base          INTEGER,
+	price_final         INTEGER,
+	price_text          TEXT,
+	discount_percentage INTEGER,
+	release_date        DATETIME,
+	release_date_text   TEXT,
+	reviews_count       INTEGER,
+	reviews_percentage  INTEGER,
+	reviews_sentiment   TEXT,
+	timestamp           DATETIME,
+	PRIMARY KEY (app_id, run_id)
+);
+CREATE TABLE IF NOT EXISTS games (
+	app_id                   INTEGER,
+	run_id                   TEXT,
+	name                     TEXT,
+	title                    TEXT,
+	url                      TEXT,
+	available                INTEGER,
+	coming_soon              INTEGER,
+	early_access             INTEGER,
+	free                     INTEGER,
+	currency                 TEXT,
+	price_base               INTEGER,
+	price_final              INTEGER,
+	discount_percentage      INTEGER,
+	discount_end             DATETIME,
+	description              TEXT,
+	release_date             DATETIME,
+	reviews_all_count        INTEGER,
+	reviews_all_percentage   INTEGER,
+	reviews_all_sentiment    TEXT,
+	reviews_recent_count     INTEGER,
+	reviews_recent_percentage INTEGER,
+	reviews_recent_sentiment TEXT,
+	website                  TEXT,
+	timestamp                DATETIME,
+	PRIMARY KEY (app_id, run_id)
+);
+CREATE TABLE IF NOT EXISTS game_categories (
+	app_id INTEGER,
+	run_id TEXT,
+	name   TEXT,
+	url    TEXT
+);
+CREATE TABLE IF NOT EXISTS game_developers (
+	app_id INTEGER,
+	run_id TEXT,
+	name   TEXT,
+	url    TEXT
+);
+CREATE TABLE IF NOT EXISTS game_genres (
+	app_id INTEGER,
+	run_id TEXT,
+	name   TEXT,
+	url    TEXT
+);
+CREATE TABLE IF NOT EXISTS game_languages (
+	app_id    INTEGER,
+	run_id    TEXT,
+	name      TEXT,
+	audio     INTEGER,
+	interface INTEGER,
+	subtitles INTEGER
+);
+CREATE TABLE IF NOT EXISTS game_publishers (
+	app_id INTEGER,
+	run_id TEXT,
+	name   TEXT,
+	url    TEXT
+);
+CREATE TABLE IF NOT EXISTS game_purchase_options (
+	app_id              INTEGER,
+	run_id              TEXT,
+	name                TEXT,
+	package_id          INTEGER,
+	bundle_id           INTEGER,
+	subscription        INTEGER,
+	free                INTEGER,
+	price_base          INTEGER,
+	price_final         INTEGER,
+	discount_percentage INTEGER,
+	discount_end        DATETIME
+);
+CREATE TABLE IF NOT EXISTS game_requirements (
+	app_id    INTEGER,
+	run_id    TEXT,
+	kind      TEXT,
+	os        TEXT,
+	name      TEXT,
+	processor TEXT,
+	memory    TEXT,
+	graphics  TEXT,
+	directx   TEXT,
+	network   TEXT,
+	storage   TEXT,
+	soundcard TEXT
+);
+CREATE TABLE IF NOT EXISTS game_tags (
+	app_id INTEGER,
+	run_id TEXT,
+	name   TEXT,
+	url    TEXT
+);
+CREATE TABLE IF NOT EXISTS game_summaries (
+	app_id  INTEGER,
+	run_id  TEXT,
+	title   TEXT,
+	url     TEXT,
+	summary TEXT
+);
+CREATE TABLE IF NOT EXISTS charts (
+	app_id              INTEGER,
+	run_id              TEXT,
+	name                TEXT,
+	title               TEXT,
+	url                 TEXT,
+	delta               DATETIME,
+	player_peak_24_hour INTEGER,
+	player_peak_all     INTEGER,
+	player_peak_delta   INTEGER,
+	timestamp           DATETIME,
+	PRIMARY KEY (app_id, run_id)
+);
+CREATE TABLE IF NOT EXISTS chart_growth (
+	app_id          INTEGER,
+	run_id          TEXT,
+	month           TEXT,
+	players_average REAL,
+	players_peak    INTEGER,
+	gain            REAL,
+	gain_percentage REAL
+);
+`
+
+var steamerSQLiteGameTables = []string{
+	"game_categories",
+	"game_developers",
+	"game_genres",
+	"game_languages",
+	"game_publishers",
+	"game_purchase_options",
+	"game_requirements",
+	"game_tags"}
+
+type SQLiteStore struct {
+	Name  string
+	RunID string
+
+	db *sql.DB
+}
+
+func NewSQLiteStore(name, runID string) (*SQLiteStore, error) {
+	if len(runID) == 0 {
+		return nil, errors.New("SQLiteStore.RunID empty")
+	}
+	err := os.MkdirAll(filepath.Dir(name), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", name+"?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(steamerSQLiteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{
+		Name:  name,
+		RunID: runID,
+		db:    db}, nil
+}
+
+func (sqliteStore *SQLiteStore) Close() error {
+	return sqliteStore.db.Close()
+}
+
+func (sqliteStore *SQLiteStore) VisitedURL(URL *url.URL) (time.Time, bool, error) {
+	var steamerVisit SteamerVisit
+	err := sqliteStore.db.QueryRow(`SELECT status_code, time_end FROM visits WHERE url_key = ?`, NormalizeURL(URL)).Scan(&steamerVisit.StatusCode, &steamerVisit.TimeEnd)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, true, err
+	}
+	return steamerVisit.TimeEnd, steamerVisit.OK(), nil
+}
+
+func (sqliteStore *SQLiteStore) WriteVisit(s *Snapshot) error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return err
+	}
+	steamerVisit := SteamerVisit{
+		StatusCode: s.StatusCode,
+		TimeEnd:    s.TimeEnd}
+	if steamerVisit.OK() != true {
+		return nil
+	}
+	_, err = sqliteStore.db.Exec(`INSERT OR REPLACE INTO visits (url_key, status_code, time_end) VALUES (?, ?, ?)`, NormalizeURL(u), steamerVisit.StatusCode, steamerVisit.TimeEnd)
+	return err
+}
+
+func (sqliteStore *SQLiteStore) ReadSnapshotBody(hash string) ([]byte, error) {
+	var b []byte
+	err := sqliteStore.db.QueryRow(`SELECT body FROM snapshot_bodies WHERE hash = ?`, hash).Scan(&b)
+	if err != nil {
+		return nil, err
+	}
+	return decompressSnapshotBody(b)
+}
+
+func (sqliteStore *SQLiteStore) ReadSnapshotReplay(URL *url.URL) (SnapshotReplay, bool, error) {
+	var snapshotReplay SnapshotReplay
+	err := sqliteStore.db.QueryRow(`SELECT url, status_code, time_end, body_hash FROM snapshots WHERE url_key = ? AND body_hash IS NOT NULL AND body_hash != '' AND status_code >= 200 AND status_code < 300 ORDER BY time_end DESC LIMIT 1`, NormalizeURL(URL)).Scan(&snapshotReplay.URL, &snapshotReplay.StatusCode, &snapshotReplay.TimeEnd, &snapshotReplay.BodyHash)
+	if err == sql.ErrNoRows {
+		return snapshotReplay, false, nil
+	}
+	if err != nil {
+		return snapshotReplay, false, err
+	}
+	return snapshotReplay, true, nil
+}
+
+func (sqliteStore *SQLiteStore) ReadSnapshotReplays() ([]SnapshotReplay, error) {
+	snapshotReplays := []SnapshotReplay{}
+	rows, err := sqliteStore.db.Query(`SELECT url, status_code, time_end, body_hash FROM snapshots WHERE body_hash IS NOT NULL AND body_hash != ''`)
+	if err != nil {
+		return snapshotReplays, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var snapshotReplay SnapshotReplay
+		err = rows.Scan(&snapshotReplay.URL, &snapshotReplay.StatusCode, &snapshotReplay.TimeEnd, &snapshotReplay.BodyHash)
+		if err != nil {
+			return snapshotReplays, err
+		}
+		snapshotReplays = append(snapshotReplays, snapshotReplay)
+	}
+	return snapshotReplays, rows.Err()
+}
+
+func (sqliteStore *SQLiteStore) WriteSnapshot(s *Snapshot) error {
+	var host string
+	URLKey := s.URL
+	if u, err := url.Parse(s.URL); err == nil {
+		host = u.Host
+		URLKey = NormalizeURL(u)
+	}
+	tx, err := sqliteStore.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if len(s.BodyHash) > 0 {
+		b, err := compressSnapshotBody(s.body)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT OR IGNORE INTO snapshot_bodies (hash, body) VALUES (?, ?)`, s.BodyHash, b)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`INSERT INTO snapshots (run_id, app_id, url, host, method, status, status_code, request_ok, response_ok, attempts, time_start, time_end, time_duration, body_hash, cache, url_key) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sqliteStore.RunID, parseSteamAppID(s.URL), s.URL, host, s.Method, s.Status, s.StatusCode, s.RequestOK, s.ResponseOK, len(s.Attempts), s.TimeStart, s.TimeEnd, int64(s.TimeDuration), s.BodyHash, s.Cache, URLKey)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (sqliteStore *SQLiteStore) WriteSteamChartPage(s *SteamChartPage) error {
+	tx, err := sqliteStore.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`INSERT OR REPLACE INTO charts (app_id, run_id, name, title, url, delta, player_peak_24_hour, player_peak_all, player_peak_delta, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.AppID, sqliteStore.RunID, s.Name, s.Title, s.URL, s.Delta, s.PlayerPeak24Hour, s.PlayerPeakAll, s.PlayerPeakDelta, s.Timestamp)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM chart_growth WHERE app_id = ? AND run_id = ?`, s.AppID, sqliteStore.RunID)
+	if err != nil {
+		return err
+	}
+	for _, x := range s.Growth {
+		_, err = tx.Exec(`INSERT INTO chart_growth (app_id, run_id, month, players_average, players_peak, gain, gain_percentage) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			s.AppID, sqliteStore.RunID, x.Month, x.PlayersAverage, x.PlayersPeak, x.Gain, x.GainPercentage)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (sqliteStore *SQLiteStore) WriteSteamGameAbbreviation(s *SteamGameAbbreviation) error {
+	b, err := json.Marshal(s.TagID)
+	if err != nil {
+		return err
+	}
+	platforms, err := json.Marshal(s.Platforms)
+	if err != nil {
+		return err
+	}
+	_, err = sqliteStore.db.Exec(`INSERT OR REPLACE INTO search_results (app_id, run_id, bundle_id, package_id, name, title, tag_ids, url, platforms, price_base, price_final, price_text, discount_percentage, release_date, release_date_text, reviews_count, reviews_percentage, reviews_sentiment, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.AppID, sqliteStore.RunID, s.BundleID, s.PackageID, s.Name, s.Title, string(b), s.URL,
+		string(platforms), s.PriceBase, s.PriceFinal, s.PriceText, s.DiscountPercentage, s.ReleaseDate, s.ReleaseDateText,
+		s.Reviews.Count, s.Reviews.Percentage, s.Reviews.Sentiment, s.Timestamp)
+	return err
+}
+
+func (sqliteStore *SQLiteStore) WriteSteamGamePage(s *SteamGamePage) error {
+	tx, err := sqliteStore.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`INSERT OR REPLACE INTO games (app_id, run_id, name, title, url, available, coming_soon, early_access, free, currency, price_base, price_final, discount_percentage, discount_end, description, release_date, reviews_all_count, reviews_all_percentage, reviews_all_sentiment, reviews_recent_count, reviews_recent_percentage, reviews_recent_sentiment, website, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.AppID, sqliteStore.RunID, s.Name, s.Title, s.URL, s.Available, s.ComingSoon, s.EarlyAccess,
+		s.Free, s.Currency, s.PriceBase, s.PriceFinal, s.DiscountPercentage, s.DiscountEnd,
+		s.Description, s.ReleaseDate,
+		s.ReviewsAll.Count, s.ReviewsAll.Percentage, s.ReviewsAll.Sentiment,
+		s.ReviewsRecent.Count, s.ReviewsRecent.Percentage, s.ReviewsRecent.Sentiment,
+		s.Website, s.Timestamp)
+	if err != nil {
+		return err
+	}
+	for _, table := range steamerSQLiteGameTables {
+		_, err = tx.Exec(`DELETE FROM `+table+` WHERE app_id = ? AND run_id = ?`, s.AppID, sqliteStore.RunID)
+		if err != nil {
+			return err
+		}
+	}
+	for _, x := range s.Categories {
+		if err = sqliteStore.insertName(tx, "game_categories", s.AppID, x.Name, x.URL); err != nil {
+			return err
+		}
+	}
+	for _, x := range s.Developers {
+		if err = sqliteStore.insertName(tx, "game_developers", s.AppID, x.Name, x.URL); err != nil {
+			return err
+		}
+	}
+	for _, x := range s.Genres {
+		if err = sqliteStore.insertName(tx, "game_genres", s.AppID, x.Name, x.URL); err != nil {
+			return err
+		}
+	}
+	for _, x := range s.Publishers {
+		if err = sqliteStore.insertName(tx, "game_publishers", s.AppID, x.Name, x.URL); err != nil {
+			return err
+		}
+	}
+	for _, x := range s.Tags {
+		if err = sqliteStore.insertName(tx, "game_tags", s.AppID, x.Name, x.URL); err != nil {
+			return err
+		}
+	}
+	for _, x := range s.Languages {
+		_, err = tx.Exec(`INSERT INTO game_languages (app_id, run_id, name, audio, interface, subtitles) VALUES (?, ?, ?, ?, ?, ?)`,
+			s.AppID, sqliteStore.RunID, x.Name, x.Audio, x.Interface, x.Subtitles)
+		if err != nil {
+			return err
+		}
+	}
+	for _, x := range s.PurchaseOptions {
+		_, err = tx.Exec(`INSERT INTO game_purchase_options (app_id, run_id, name, package_id, bundle_id, subscription, free, price_base, price_final, discount_percentage, discount_end) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			s.AppID, sqliteStore.RunID, x.Name, x.PackageID, x.BundleID, x.Subscription, x.Free, x.PriceBase, x.PriceFinal, x.DiscountPercentage, x.DiscountEnd)
+		if err != nil {
+			return err
+		}
+	}
+	for kind, requirements := range map[string][]SteamPageGameRequirement{
+		"minimum":     s.RequirementsMinimum,
+		"recommended": s.RequirementsRecommended} {
+		for _, x := range requirements {
+			_, err = tx.Exec(`INSERT INTO game_requirements (app_id, run_id, kind, os, name, processor, memory, graphics, directx, network, storage, soundcard) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				s.AppID, sqliteStore.RunID, kind, x.OS, x.Name, x.Processor, x.Memory, x.Graphics, x.DirectX, x.Network, x.Storage, x.SoundCard)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (sqliteStore *SQLiteStore) WriteSteamGameSummary(s *SteamGameSummary) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = sqliteStore.db.Exec(`INSERT INTO game_summaries (app_id, run_id, title, url, summary) VALUES (?, ?, ?, ?, ?)`,
+		s.AppID, sqliteStore.RunID, s.Title, s.URL, string(b))
+	return err
+}
+
+func (sqliteStore *SQLiteStore) WriteSteamSummaryCSV(name string, s *[]SteamSummaryCSV) error {
+	if len(*s) == 0 {
+		return errors.New("s cannot be empty")
+	}
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	if err := writer.Write((*s)[0].Heading); err != nil {
+		return err
+	}
+	for _, steamSummaryCSV := range *s {
+		if err := writer.Write(steamSummaryCSV.Values); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	_, err := sqliteStore.db.Exec(`INSERT OR REPLACE INTO run_summary_csv (run_id, name, csv) VALUES (?, ?, ?)`,
+		sqliteStore.RunID, name, buffer.String())
+	return err
+}
+
+func (sqliteStore *SQLiteStore) WriteSteamerDeadLetter(s *SteamerDeadLetter) error {
+	tx, err := sqliteStore.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`DELETE FROM failures WHERE run_id = ?`, sqliteStore.RunID)
+	if err != nil {
+		return err
+	}
+	for _, x := range s.Failures {
+		_, err = tx.Exec(`INSERT INTO failures (run_id, stage, page, url, status, status_code, error, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			sqliteStore.RunID, x.Stage, x.Page, x.URL, x.Status, x.StatusCode, x.Error, x.Timestamp)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (sqliteStore *SQLiteStore) WriteSteamerLog(s *SteamerLog) error {
+	b, err := json.Marshal(s.PagesOK)
+	if err != nil {
+		return err
+	}
+	config, err := json.Marshal(s.Config)
+	if err != nil {
+		return err
+	}
+	_, err = sqliteStore.db.Exec(`INSERT OR REPLACE INTO runs (run_id, config, depth, pages_from, pages_to, pages_ok, failures, incomplete, terminate_zero, time_start, time_end, time_duration, total_count, warc_failures, stored) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sqliteStore.RunID, string(config), s.Depth, s.PagesFrom, s.PagesTo, string(b), s.Failures, s.Incomplete, s.TerminateZero, s.TimeStart, s.TimeEnd, int64(s.TimeDuration), s.TotalCount, s.WARCFailures, s.Stored)
+	return err
+}
+
+func (sqliteStore *SQLiteStore) WriteSteamerSummary(s *SteamerSummary) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = sqliteStore.db.Exec(`INSERT OR REPLACE INTO run_summaries (run_id, games, summary) VALUES (?, ?, ?)`,
+		sqliteStore.RunID, s.Games, string(b))
+	return err
+}
+
+func (sqliteStore *SQLiteStore) insertName(tx *sql.Tx, table string, appID int, name, URL string) error {
+	_, err := tx.Exec(`INSERT INTO `+table+` (app_id, run_id, name, url) VALUES (?, ?, ?, ?)`, appID, sqliteStore.RunID, name, URL)
+	return err
+}
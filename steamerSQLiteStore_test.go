@@ -0,0 +1,72 @@
+package steamer
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "steamer.db")
+	sqliteStore, err := NewSQLiteStore(name, "run-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: newSteamerTestTransport(map[int][]int{1: {10}})}
+	URL := "https://store.steampowered.com/app/10/"
+	snapshot := NewSnapshot(context.Background(), client, nil, http.MethodGet, URL, nil)
+	if err := sqliteStore.WriteSnapshot(snapshot); err != nil {
+		t.Fatalf("SQLiteStore.WriteSnapshot: %v", err)
+	}
+	if err := sqliteStore.WriteVisit(snapshot); err != nil {
+		t.Fatalf("SQLiteStore.WriteVisit: %v", err)
+	}
+	steamGamePage, err := newSteamGamePageFromDocument(URL, snapshot.Document())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteStore.WriteSteamGamePage(steamGamePage); err != nil {
+		t.Fatalf("SQLiteStore.WriteSteamGamePage: %v", err)
+	}
+	if err := sqliteStore.WriteSteamerLog(&SteamerLog{PagesOK: NewSteamerLogPageOK(), Stored: 1, WARCFailures: 2}); err != nil {
+		t.Fatalf("SQLiteStore.WriteSteamerLog: %v", err)
+	}
+	if err := sqliteStore.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// reopening runs the schema again over the existing tables
+	sqliteStore, err = NewSQLiteStore(name, "run-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqliteStore.Close()
+	// the visit is keyed by the normalized URL, so another form of the same URL finds it
+	u, _ := url.Parse("https://store.steampowered.com/app/10")
+	if _, ok, err := sqliteStore.VisitedURL(u); err != nil || ok != true {
+		t.Errorf("SQLiteStore.VisitedURL = %v, %v, want true, nil", ok, err)
+	}
+	snapshotReplay, ok, err := sqliteStore.ReadSnapshotReplay(u)
+	if err != nil || ok != true {
+		t.Fatalf("SQLiteStore.ReadSnapshotReplay = %v, %v, want true, nil", ok, err)
+	}
+	if snapshotReplay.BodyHash != snapshot.BodyHash {
+		t.Errorf("SnapshotReplay.BodyHash = %q, want %q", snapshotReplay.BodyHash, snapshot.BodyHash)
+	}
+	body, err := sqliteStore.ReadSnapshotBody(snapshotReplay.BodyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != string(snapshot.Body()) {
+		t.Errorf("SQLiteStore.ReadSnapshotBody = %q, want %q", body, snapshot.Body())
+	}
+	var games int
+	if err := sqliteStore.db.QueryRow(`SELECT COUNT(*) FROM games WHERE app_id = 10 AND run_id = 'run-1'`).Scan(&games); err != nil || games != 1 {
+		t.Errorf("games = %d, %v, want 1", games, err)
+	}
+	var stored, warcFailures int
+	if err := sqliteStore.db.QueryRow(`SELECT stored, warc_failures FROM runs WHERE run_id = 'run-1'`).Scan(&stored, &warcFailures); err != nil || stored != 1 || warcFailures != 2 {
+		t.Errorf("runs stored, warc_failures = %d, %d, %v, want 1, 2", stored, warcFailures, err)
+	}
+}
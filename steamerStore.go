@@ -0,0 +1,26 @@
+package steamer
+
+import (
+	"net/url"
+	"time"
+)
+
+type Store interface {
+	VisitedURL(URL *url.URL) (time.Time, bool, error)
+	WriteSnapshot(s *Snapshot) error
+	WriteSteamChartPage(s *SteamChartPage) error
+	WriteSteamGameAbbreviation(s *SteamGameAbbreviation) error
+	WriteSteamGamePage(s *SteamGamePage) error
+	WriteSteamGameSummary(s *SteamGameSummary) error
+	WriteSteamSummaryCSV(name string, s *[]SteamSummaryCSV) error
+	WriteSteamerDeadLetter(s *SteamerDeadLetter) error
+	WriteSteamerLog(s *SteamerLog) error
+	WriteSteamerSummary(s *SteamerSummary) error
+	WriteVisit(s *Snapshot) error
+}
+
+type ReplayStore interface {
+	ReadSnapshotBody(hash string) ([]byte, error)
+	ReadSnapshotReplay(URL *url.URL) (SnapshotReplay, bool, error)
+	ReadSnapshotReplays() ([]SnapshotReplay, error)
+}
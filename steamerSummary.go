@@ -1,22 +1,23 @@
-package main
+package steamer
 
 import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/user"
 	"path/filepath"
 )
 
 type SteamerSummary struct {
-	Developers map[string][]string `json:"developers"`
-	Games      int                 `json:"games"`
-	Genres     map[string]int      `json:"genres"`
-	PagesFrom  int                 `json:"pages_from"`
-	PagesTo    int                 `json:"pages_to"`
-	Publishers map[string][]string `json:"publishers"`
-	Sentiments map[string]int      `json:"sentiments"`
+	Developers map[string][]int `json:"developers"`
+	Games      int              `json:"games"`
+	Genres     map[string]int   `json:"genres"`
+	Incomplete bool             `json:"incomplete"`
+	PagesFrom  int              `json:"pages_from"`
+	PagesTo    int              `json:"pages_to"`
+	Publishers map[string][]int `json:"publishers"`
+	Sentiments map[string]int   `json:"sentiments"`
+	Titles     map[int]string   `json:"titles"`
 }
 
 func writeSteamerSummary(fullpath string, s *SteamerSummary) error {
@@ -33,13 +34,3 @@ func writeSteamerSummary(fullpath string, s *SteamerSummary) error {
 	err = ioutil.WriteFile(fullname, b, os.ModePerm)
 	return err
 }
-
-func writeSteamerSummaryDefault(s *SteamerSummary) error {
-	user, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-	fullpath := filepath.Join(user.HomeDir, "Desktop", "steambot")
-	err = writeSteamerSummary(fullpath, s)
-	return err
-}
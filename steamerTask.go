@@ -0,0 +1,32 @@
+package steamer
+
+import "fmt"
+
+const (
+	SteamerStageChart  string = "chart"
+	SteamerStageGame   string = "game"
+	SteamerStageSearch string = "search"
+)
+
+type SteamerTask struct {
+	Game  *SteamGamePage `json:"game,omitempty"`
+	Page  int            `json:"page"`
+	Stage string         `json:"stage"`
+	URL   string         `json:"URL"`
+}
+
+func NewSteamerTask(stage, URL string, page int, game *SteamGamePage) SteamerTask {
+	return SteamerTask{
+		Game:  game,
+		Page:  page,
+		Stage: stage,
+		URL:   URL}
+}
+
+func (steamerTask SteamerTask) Key() string {
+	URL, err := ParseNormalizedURL(steamerTask.URL)
+	if err != nil {
+		URL = steamerTask.URL
+	}
+	return fmt.Sprintf("%s %s", steamerTask.Stage, URL)
+}
@@ -0,0 +1,60 @@
+package steamer
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// snr is Steam's click tracking and never changes the page that is served
+var steamerURLIgnoredParams = map[string]bool{
+	"snr": true}
+
+func NormalizeURL(URL *url.URL) string {
+	scheme := strings.ToLower(URL.Scheme)
+	if len(scheme) == 0 {
+		scheme = "https"
+	}
+	host := strings.ToLower(URL.Hostname())
+	if port := URL.Port(); len(port) > 0 && (scheme == "http" && port != "80" || scheme == "https" && port != "443") {
+		host = fmt.Sprintf("%s:%s", host, port)
+	}
+	// store and charts pages are the same page whatever slug or query follows the AppID
+	if appID := parseSteamAppID(URL.Path); appID > -1 && (host == SteamStoreHost || host == SteamChartsHost) {
+		return fmt.Sprintf("%s://%s/app/%d", scheme, host, appID)
+	}
+	path := URL.EscapedPath()
+	if len(path) == 0 {
+		path = "/"
+	}
+	query := URL.Query()
+	keys := []string{}
+	for key := range query {
+		if steamerURLIgnoredParams[key] {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	params := []string{}
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, fmt.Sprintf("%s=%s", url.QueryEscape(key), url.QueryEscape(value)))
+		}
+	}
+	if len(params) == 0 {
+		return fmt.Sprintf("%s://%s%s", scheme, host, path)
+	}
+	return fmt.Sprintf("%s://%s%s?%s", scheme, host, path, strings.Join(params, "&"))
+}
+
+func ParseNormalizedURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return NormalizeURL(u), nil
+}
@@ -0,0 +1,32 @@
+package steamer
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		URL  string
+		want string
+	}{
+		{"https://store.steampowered.com/app/10/CounterStrike/?snr=1_7_7_230_150_1", "https://store.steampowered.com/app/10"},
+		{"https://store.steampowered.com/app/10", "https://store.steampowered.com/app/10"},
+		{"https://steamcharts.com/app/730#all-data", "https://steamcharts.com/app/730"},
+		{"HTTPS://Store.SteamPowered.com:443/search/?term=a&sort_by=Released_DESC&snr=1_7", "https://store.steampowered.com/search/?sort_by=Released_DESC&term=a"},
+		{"https://store.steampowered.com/search/?page=2&page=1", "https://store.steampowered.com/search/?page=1&page=2"},
+		{"//store.steampowered.com/search?b=2&a=1", "https://store.steampowered.com/search?a=1&b=2"},
+		{"http://example.com:80", "http://example.com/"},
+		{"http://example.com:8080/app/10", "http://example.com:8080/app/10"},
+		{"https://store.steampowered.com/search/?term=half+life", "https://store.steampowered.com/search/?term=half+life"},
+	}
+	for _, test := range tests {
+		u, err := url.Parse(test.URL)
+		if err != nil {
+			t.Fatalf("url.Parse(%q): %v", test.URL, err)
+		}
+		if got := NormalizeURL(u); got != test.want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", test.URL, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package steamer
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const SteamerVisitIndexFlush int = 100
+
+const SteamerVisitIndexTimeout time.Duration = time.Second * 30
+
+var steamerVisitIndexBucket = []byte("visits")
+
+type SteamerVisitIndex struct {
+	Name string
+
+	mu      *sync.RWMutex
+	pending map[string]SteamerVisit
+	visits  map[string]SteamerVisit
+}
+
+type SteamerVisit struct {
+	StatusCode int       `json:"status_code"`
+	TimeEnd    time.Time `json:"time_end"`
+}
+
+func (steamerVisit SteamerVisit) OK() bool {
+	// a failed fetch (non-2xx or no response) leaves the page eligible for the next run
+	return steamerVisit.StatusCode >= 200 && steamerVisit.StatusCode < 300 && steamerVisit.TimeEnd.IsZero() != true
+}
+
+func NewSteamerVisitIndex(name string, backfill func() (map[string]SteamerVisit, error)) (*SteamerVisitIndex, error) {
+	// the index is read into memory and the file released, so farm processes sharing -out
+	// only hold the bolt lock while loading and flushing
+	steamerVisitIndex := &SteamerVisitIndex{
+		Name:    name,
+		mu:      &sync.RWMutex{},
+		pending: map[string]SteamerVisit{},
+		visits:  map[string]SteamerVisit{}}
+	_, err := os.Stat(name)
+	if os.IsNotExist(err) && backfill != nil {
+		visits, err := backfill()
+		if err != nil {
+			return nil, err
+		}
+		for key, steamerVisit := range visits {
+			steamerVisitIndex.visits[key] = steamerVisit
+			steamerVisitIndex.pending[key] = steamerVisit
+		}
+		return steamerVisitIndex, steamerVisitIndex.Flush()
+	}
+	if os.IsNotExist(err) {
+		return steamerVisitIndex, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(name, 0644, &bolt.Options{ReadOnly: true, Timeout: SteamerVisitIndexTimeout})
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(steamerVisitIndexBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var steamerVisit SteamerVisit
+			if err := json.Unmarshal(v, &steamerVisit); err != nil {
+				return err
+			}
+			steamerVisitIndex.visits[string(k)] = steamerVisit
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return steamerVisitIndex, nil
+}
+
+func (steamerVisitIndex *SteamerVisitIndex) Flush() error {
+	steamerVisitIndex.mu.Lock()
+	defer steamerVisitIndex.mu.Unlock()
+	return steamerVisitIndex.flush()
+}
+
+func (steamerVisitIndex *SteamerVisitIndex) flush() error {
+	if len(steamerVisitIndex.pending) == 0 {
+		return nil
+	}
+	db, err := bolt.Open(steamerVisitIndex.Name, 0644, &bolt.Options{Timeout: SteamerVisitIndexTimeout})
+	if err != nil {
+		return err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(steamerVisitIndexBucket)
+		if err != nil {
+			return err
+		}
+		for key, steamerVisit := range steamerVisitIndex.pending {
+			v, err := json.Marshal(steamerVisit)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(key), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	steamerVisitIndex.pending = map[string]SteamerVisit{}
+	return nil
+}
+
+func (steamerVisitIndex *SteamerVisitIndex) Len() int {
+	steamerVisitIndex.mu.RLock()
+	defer steamerVisitIndex.mu.RUnlock()
+	return len(steamerVisitIndex.visits)
+}
+
+func (steamerVisitIndex *SteamerVisitIndex) Visit(URL *url.URL, statusCode int, timeEnd time.Time) error {
+	key := NormalizeURL(URL)
+	steamerVisit := SteamerVisit{
+		StatusCode: statusCode,
+		TimeEnd:    timeEnd}
+	if steamerVisit.OK() != true {
+		return nil
+	}
+	steamerVisitIndex.mu.Lock()
+	defer steamerVisitIndex.mu.Unlock()
+	steamerVisitIndex.visits[key] = steamerVisit
+	steamerVisitIndex.pending[key] = steamerVisit
+	// flushing in batches bounds what a crash can lose without a bolt commit per page
+	if len(steamerVisitIndex.pending) < SteamerVisitIndexFlush {
+		return nil
+	}
+	return steamerVisitIndex.flush()
+}
+
+func (steamerVisitIndex *SteamerVisitIndex) Visited(URL *url.URL) (SteamerVisit, bool) {
+	steamerVisitIndex.mu.RLock()
+	defer steamerVisitIndex.mu.RUnlock()
+	steamerVisit, ok := steamerVisitIndex.visits[NormalizeURL(URL)]
+	return steamerVisit, ok && steamerVisit.OK()
+}
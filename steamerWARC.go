@@ -0,0 +1,183 @@
+package steamer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const SteamerWARCMaxSize int64 = 1 << 30
+
+type SteamerWARC struct {
+	Fullpath string
+	MaxSize  int64
+	Names    []string
+	RunID    string
+
+	file *os.File
+	mu   *sync.Mutex
+	size int64
+}
+
+func NewSteamerWARC(fullpath string, runID string, maxSize int64) (*SteamerWARC, error) {
+	if maxSize <= 0 {
+		maxSize = SteamerWARCMaxSize
+	}
+	err := os.MkdirAll(fullpath, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	return &SteamerWARC{
+		Fullpath: fullpath,
+		MaxSize:  maxSize,
+		Names:    []string{},
+		RunID:    runID,
+		mu:       &sync.Mutex{}}, nil
+}
+
+func (steamerWARC *SteamerWARC) Close() error {
+	steamerWARC.mu.Lock()
+	defer steamerWARC.mu.Unlock()
+	if steamerWARC.file == nil {
+		return nil
+	}
+	err := steamerWARC.file.Close()
+	steamerWARC.file = nil
+	return err
+}
+
+func (steamerWARC *SteamerWARC) Write(s *Snapshot) error {
+	if s.request == nil || s.response == nil {
+		return errors.New("Snapshot.response empty")
+	}
+	// cache hits and 304s were not fetched from the network this run, so there is nothing to archive
+	if s.Cache == SnapshotCacheHit || s.Cache == SnapshotCacheNotModified {
+		return nil
+	}
+	URL := s.request.URL.String()
+	date := s.TimeStart
+	if date.IsZero() {
+		date = time.Now()
+	}
+	requestID, responseID, metadataID := newSteamerWARCRecordID(), newSteamerWARCRecordID(), newSteamerWARCRecordID()
+	var request bytes.Buffer
+	fmt.Fprintf(&request, "%s %s HTTP/1.1\r\n", s.request.Method, s.request.URL.RequestURI())
+	fmt.Fprintf(&request, "Host: %s\r\n", s.request.URL.Host)
+	s.request.Header.Write(&request)
+	request.WriteString("\r\n")
+	var response bytes.Buffer
+	fmt.Fprintf(&response, "HTTP/%d.%d %s\r\n", s.response.ProtoMajor, s.response.ProtoMinor, s.response.Status)
+	header := s.response.Header.Clone()
+	// the transport has already removed any content or transfer encoding from the body that is stored
+	header.Del("Content-Encoding")
+	header.Del("Transfer-Encoding")
+	header.Set("Content-Length", strconv.Itoa(len(s.body)))
+	header.Write(&response)
+	response.WriteString("\r\n")
+	response.Write(s.body)
+	metadata, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	payloadDigest := sha256.Sum256(s.body)
+	records := [][]byte{
+		newSteamerWARCRecord("request", requestID, date, URL, "application/http; msgtype=request", request.Bytes(), map[string]string{
+			"WARC-Concurrent-To": responseID}),
+		newSteamerWARCRecord("response", responseID, date, URL, "application/http; msgtype=response", response.Bytes(), map[string]string{
+			"WARC-Payload-Digest": fmt.Sprintf("sha256:%s", hex.EncodeToString(payloadDigest[:]))}),
+		newSteamerWARCRecord("metadata", metadataID, date, URL, "application/json", metadata, map[string]string{
+			"WARC-Refers-To": responseID})}
+	steamerWARC.mu.Lock()
+	defer steamerWARC.mu.Unlock()
+	// the request, response and metadata of one fetch always land in the same file
+	if steamerWARC.file == nil || steamerWARC.size >= steamerWARC.MaxSize {
+		if err := steamerWARC.rotate(); err != nil {
+			return err
+		}
+	}
+	for _, record := range records {
+		if err := steamerWARC.append(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (steamerWARC *SteamerWARC) rotate() error {
+	if steamerWARC.file != nil {
+		if err := steamerWARC.file.Close(); err != nil {
+			return err
+		}
+	}
+	filename := fmt.Sprintf("steamer-%s-%05d.warc.gz", steamerWARC.RunID, len(steamerWARC.Names))
+	fullname := filepath.Join(steamerWARC.Fullpath, filename)
+	file, err := os.OpenFile(fullname, os.O_CREATE|os.O_EXCL|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	steamerWARC.file, steamerWARC.size = file, 0
+	steamerWARC.Names = append(steamerWARC.Names, fullname)
+	warcinfo := fmt.Sprintf("software: steamer\r\nformat: WARC File Format 1.1\r\nisPartOf: %s\r\n", steamerWARC.RunID)
+	return steamerWARC.append(newSteamerWARCRecord("warcinfo", newSteamerWARCRecordID(), time.Now(), "", "application/warc-fields", []byte(warcinfo), map[string]string{
+		"WARC-Filename": filename}))
+}
+
+func (steamerWARC *SteamerWARC) append(record []byte) error {
+	// every record is its own gzip member so readers can seek straight to it
+	var b bytes.Buffer
+	gzipWriter := gzip.NewWriter(&b)
+	if _, err := gzipWriter.Write(record); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	n, err := steamerWARC.file.Write(b.Bytes())
+	steamerWARC.size = steamerWARC.size + int64(n)
+	return err
+}
+
+func newSteamerWARCRecord(recordType, recordID string, date time.Time, URL, contentType string, block []byte, fields map[string]string) []byte {
+	var b bytes.Buffer
+	b.WriteString("WARC/1.1\r\n")
+	fmt.Fprintf(&b, "WARC-Type: %s\r\n", recordType)
+	fmt.Fprintf(&b, "WARC-Record-ID: %s\r\n", recordID)
+	fmt.Fprintf(&b, "WARC-Date: %s\r\n", date.UTC().Format(time.RFC3339Nano))
+	if len(URL) > 0 {
+		fmt.Fprintf(&b, "WARC-Target-URI: %s\r\n", URL)
+	}
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s: %s\r\n", key, fields[key])
+	}
+	fmt.Fprintf(&b, "Content-Type: %s\r\n", contentType)
+	fmt.Fprintf(&b, "Content-Length: %d\r\n", len(block))
+	b.WriteString("\r\n")
+	b.Write(block)
+	b.WriteString("\r\n\r\n")
+	return b.Bytes()
+}
+
+func newSteamerWARCRecordID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b)
+	return fmt.Sprintf("<urn:uuid:%s-%s-%s-%s-%s>", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
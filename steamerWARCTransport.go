@@ -0,0 +1,178 @@
+package steamer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type SteamerWARCTransport struct {
+	Names []string
+
+	index map[string]steamerWARCOffset
+	mu    *sync.Mutex
+}
+
+type steamerWARCOffset struct {
+	name   string
+	offset int64
+}
+
+type steamerWARCCountingReader struct {
+	n int64
+	r *bufio.Reader
+}
+
+func NewSteamerWARCTransport(fullpath string) (*SteamerWARCTransport, error) {
+	names := []string{fullpath}
+	if info, err := os.Stat(fullpath); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		names, err = filepath.Glob(filepath.Join(fullpath, "*.warc.gz"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(names)
+	}
+	steamerWARCTransport := &SteamerWARCTransport{
+		Names: names,
+		index: map[string]steamerWARCOffset{},
+		mu:    &sync.Mutex{}}
+	for _, name := range names {
+		if err := steamerWARCTransport.read(name); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return steamerWARCTransport, nil
+}
+
+func (steamerWARCTransport *SteamerWARCTransport) Len() int {
+	steamerWARCTransport.mu.Lock()
+	defer steamerWARCTransport.mu.Unlock()
+	return len(steamerWARCTransport.index)
+}
+
+func (steamerWARCTransport *SteamerWARCTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	steamerWARCTransport.mu.Lock()
+	steamerWARCOffset, ok := steamerWARCTransport.index[req.URL.String()]
+	steamerWARCTransport.mu.Unlock()
+	// a missing page answers like the live site would instead of failing the request, so it is not retried
+	if ok != true {
+		return &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			Header:     http.Header{},
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Request:    req,
+			Status:     "404 Not Archived",
+			StatusCode: http.StatusNotFound}, nil
+	}
+	file, err := os.Open(steamerWARCOffset.name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if _, err := file.Seek(steamerWARCOffset.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	gzipReader, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return nil, err
+	}
+	gzipReader.Multistream(false)
+	header, block, err := readSteamerWARCRecord(bufio.NewReader(gzipReader))
+	if err != nil {
+		return nil, err
+	}
+	if header.Get("WARC-Type") != "response" {
+		return nil, fmt.Errorf("SteamerWARCTransport %s record is %q", req.URL, header.Get("WARC-Type"))
+	}
+	return http.ReadResponse(bufio.NewReader(bytes.NewReader(block)), req)
+}
+
+func (steamerWARCTransport *SteamerWARCTransport) read(name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// gzip reads a ByteReader without buffering ahead, so n is the exact offset of the next member
+	countingReader := &steamerWARCCountingReader{
+		r: bufio.NewReader(file)}
+	offset := countingReader.n
+	gzipReader, err := gzip.NewReader(countingReader)
+	for {
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		gzipReader.Multistream(false)
+		var header textproto.MIMEHeader
+		header, _, err = readSteamerWARCRecord(bufio.NewReader(gzipReader))
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(ioutil.Discard, gzipReader); err != nil {
+			return err
+		}
+		if header.Get("WARC-Type") == "response" {
+			steamerWARCTransport.index[header.Get("WARC-Target-URI")] = steamerWARCOffset{
+				name:   name,
+				offset: offset}
+		}
+		offset = countingReader.n
+		err = gzipReader.Reset(countingReader)
+	}
+}
+
+func (steamerWARCCountingReader *steamerWARCCountingReader) Read(p []byte) (int, error) {
+	n, err := steamerWARCCountingReader.r.Read(p)
+	steamerWARCCountingReader.n = steamerWARCCountingReader.n + int64(n)
+	return n, err
+}
+
+func (steamerWARCCountingReader *steamerWARCCountingReader) ReadByte() (byte, error) {
+	b, err := steamerWARCCountingReader.r.ReadByte()
+	if err == nil {
+		steamerWARCCountingReader.n = steamerWARCCountingReader.n + 1
+	}
+	return b, err
+}
+
+func readSteamerWARCRecord(r *bufio.Reader) (textproto.MIMEHeader, []byte, error) {
+	version, err := r.ReadString('\n')
+	if err != nil {
+		return nil, nil, err
+	}
+	if strings.HasPrefix(version, "WARC/") != true {
+		return nil, nil, errors.New("SteamerWARC record version missing")
+	}
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, nil, err
+	}
+	block := make([]byte, n)
+	if _, err := io.ReadFull(r, block); err != nil {
+		return nil, nil, err
+	}
+	return header, block, nil
+}
@@ -0,0 +1,118 @@
+package steamer
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSteamerWARCReplay(t *testing.T) {
+	tests := []struct {
+		path       string
+		body       string
+		statusCode int
+	}{
+		{"/search/?page=1", "<html>page 1</html>", http.StatusOK},
+		{"/app/10/CounterStrike/", "<html>app 10</html>", http.StatusOK},
+		{"/app/20/", "<html>gone</html>", http.StatusNotFound},
+		{"/search/results/?start=0&count=50", `{"success":1,"results_html":""}`, http.StatusOK},
+	}
+	bodies := map[string]string{}
+	statusCodes := map[string]int{}
+	for _, test := range tests {
+		bodies[test.path] = test.body
+		statusCodes[test.path] = test.statusCode
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(statusCodes[r.URL.RequestURI()])
+		w.Write([]byte(bodies[r.URL.RequestURI()]))
+	}))
+	defer server.Close()
+	fullpath := t.TempDir()
+	steamerWARC, err := NewSteamerWARC(fullpath, "run", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		snapshot := NewSnapshot(context.Background(), server.Client(), nil, http.MethodGet, server.URL+test.path, nil)
+		if err := steamerWARC.Write(snapshot); err != nil {
+			t.Fatalf("%s: SteamerWARC.Write: %v", test.path, err)
+		}
+	}
+	if err := steamerWARC.Close(); err != nil {
+		t.Fatal(err)
+	}
+	steamerWARCTransport, err := NewSteamerWARCTransport(fullpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := steamerWARCTransport.Len(); got != len(tests) {
+		t.Errorf("SteamerWARCTransport.Len() = %d, want %d", got, len(tests))
+	}
+	client := &http.Client{Transport: steamerWARCTransport}
+	for _, test := range tests {
+		snapshot := NewSnapshot(context.Background(), client, nil, http.MethodGet, server.URL+test.path, nil)
+		if snapshot.StatusCode != test.statusCode {
+			t.Errorf("%s: status code = %d, want %d", test.path, snapshot.StatusCode, test.statusCode)
+		}
+		if got := string(snapshot.Body()); got != test.body {
+			t.Errorf("%s: body = %q, want %q", test.path, got, test.body)
+		}
+	}
+	snapshot := NewSnapshot(context.Background(), client, nil, http.MethodGet, server.URL+"/app/30/", nil)
+	if snapshot.StatusCode != http.StatusNotFound {
+		t.Errorf("unarchived: status code = %d, want %d", snapshot.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestSteamerWARCRotate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(SteamerCacheHeader, r.URL.Query().Get("cache"))
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+	// every file is over the size limit after its first fetch
+	steamerWARC, err := NewSteamerWARC(t.TempDir(), "run", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cache := range []string{SnapshotCacheFetch, SnapshotCacheHit, SnapshotCacheNotModified, SnapshotCacheFetch} {
+		snapshot := NewSnapshot(context.Background(), server.Client(), nil, http.MethodGet, server.URL+"/?cache="+cache, nil)
+		if err := steamerWARC.Write(snapshot); err != nil {
+			t.Fatalf("%s: SteamerWARC.Write: %v", cache, err)
+		}
+	}
+	if err := steamerWARC.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// cache-served responses are not archived
+	if got := len(steamerWARC.Names); got != 2 {
+		t.Fatalf("len(SteamerWARC.Names) = %d, want 2", got)
+	}
+	for _, name := range steamerWARC.Names {
+		file, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(gzipReader)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, recordType := range []string{"warcinfo", "request", "response", "metadata"} {
+			if got := strings.Count(string(b), "WARC-Type: "+recordType+"\r\n"); got != 1 {
+				t.Errorf("%s: %d %s records, want 1", name, got, recordType)
+			}
+		}
+	}
+}
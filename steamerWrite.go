@@ -0,0 +1,50 @@
+package steamer
+
+import "strings"
+
+type SteamerWrite struct {
+	Abbreviation bool `json:"abbreviation" toml:"abbreviation" yaml:"abbreviation"`
+	CSV          bool `json:"CSV" toml:"csv" yaml:"csv"`
+	Chart        bool `json:"chart" toml:"chart" yaml:"chart"`
+	Game         bool `json:"game" toml:"game" yaml:"game"`
+	GameSummary  bool `json:"game_summary" toml:"game_summary" yaml:"game_summary"`
+	Log          bool `json:"log" toml:"log" yaml:"log"`
+	Snapshot     bool `json:"snapshot" toml:"snapshot" yaml:"snapshot"`
+	Summary      bool `json:"summary" toml:"summary" yaml:"summary"`
+}
+
+func NewSteamerWrite(level int) *SteamerWrite {
+	return &SteamerWrite{
+		Abbreviation: level >= 1,
+		CSV:          true,
+		Chart:        level >= 3,
+		Game:         level >= 2,
+		GameSummary:  level >= 4,
+		Log:          true,
+		Snapshot:     level > 0,
+		Summary:      true}
+}
+
+func (steamerWrite *SteamerWrite) String() string {
+	var write []string
+	for _, x := range []struct {
+		name string
+		ok   bool
+	}{
+		{"LOGS", steamerWrite.Log},
+		{"SNAPSHOTS", steamerWrite.Snapshot},
+		{"ABBR", steamerWrite.Abbreviation},
+		{"GAME", steamerWrite.Game},
+		{"CHART", steamerWrite.Chart},
+		{"GAME SUMMARY", steamerWrite.GameSummary},
+		{"SUMMARY", steamerWrite.Summary},
+		{"CSV", steamerWrite.CSV}} {
+		if x.ok {
+			write = append(write, x.name)
+		}
+	}
+	if len(write) == 0 {
+		return "NONE"
+	}
+	return strings.Join(write, " + ")
+}